@@ -0,0 +1,154 @@
+package tgtg
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Accounts manages multiple authenticated Too Good To Go clients, keyed by account email.
+// All clients share the same HTTP client (and so its transport) and ClientOptions, e.g. RateLimiter
+// set with SetRateLimiter limits combined rate of all accounts. Accounts is safe for concurrent use.
+type Accounts struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+
+	httpClient *http.Client
+	options    []ClientOption
+}
+
+// AccountFunc is a function called for single account by Accounts.ForEach.
+type AccountFunc func(ctx context.Context, email string, client *Client) error
+
+// NewAccounts returns a new Accounts manager. Given HTTP client and options are used for every added account.
+func NewAccounts(httpClient *http.Client, options ...ClientOption) *Accounts {
+	return &Accounts{
+		clients:    make(map[string]*Client),
+		httpClient: httpClient,
+		options:    options,
+	}
+}
+
+// Add creates a client for account with given email and auth context, replacing existing one if any.
+// Use Client.Auth to authenticate it from scratch, if tokens are not known yet.
+func (a *Accounts) Add(email, accessToken, refreshToken, userID string) (*Client, error) {
+	if email == "" {
+		return nil, NewArgumentError("email", "must not be empty")
+	}
+
+	client, err := New(a.httpClient, a.options...)
+	if err != nil {
+		return nil, err
+	}
+	client.SetAuthContext(accessToken, refreshToken, userID)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clients[email] = client
+
+	return client, nil
+}
+
+// Remove removes account with given email.
+func (a *Accounts) Remove(email string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.clients, email)
+}
+
+// Client returns client of account with given email.
+func (a *Accounts) Client(email string) (*Client, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	client, ok := a.clients[email]
+	return client, ok
+}
+
+// Emails returns sorted emails of all accounts.
+func (a *Accounts) Emails() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	emails := make([]string, 0, len(a.clients))
+	for email := range a.clients {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// ForEach calls fn concurrently for every account. All calls are finished before it returns.
+// If any call fails, AccountsError with errors by account email is returned.
+func (a *Accounts) ForEach(ctx context.Context, fn AccountFunc) error {
+	a.mu.RLock()
+	clients := make(map[string]*Client, len(a.clients))
+	for email, client := range a.clients {
+		clients[email] = client
+	}
+	a.mu.RUnlock()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+	)
+	for email, client := range clients {
+		wg.Add(1)
+		go func(email string, client *Client) {
+			defer wg.Done()
+			if err := fn(ctx, email, client); err != nil {
+				mu.Lock()
+				errs[email] = err
+				mu.Unlock()
+			}
+		}(email, client)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &AccountsError{Errors: errs}
+	}
+	return nil
+}
+
+// Refresh refreshes tokens of every account independently, using refresh token stored in its client.
+// Failure of one account does not affect the others, see ForEach. Refreshed tokens are set on a clone
+// of account client, see Client.Clone, which replaces it, so clients in use are never modified.
+func (a *Accounts) Refresh(ctx context.Context) error {
+	return a.ForEach(ctx, func(ctx context.Context, email string, client *Client) error {
+		refreshed := client.Clone()
+		if _, _, err := refreshed.Auth.Refresh(ctx, nil); err != nil {
+			return err
+		}
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		// Account could have been replaced or removed meanwhile.
+		if a.clients[email] == client {
+			a.clients[email] = refreshed
+		}
+		return nil
+	})
+}
+
+// ActiveOrders fetches active orders of every account, returning them by account email.
+// Orders of successful accounts are returned even if some accounts failed, see ForEach.
+func (a *Accounts) ActiveOrders(ctx context.Context) (map[string][]Order, error) {
+	var mu sync.Mutex
+	orders := make(map[string][]Order)
+
+	err := a.ForEach(ctx, func(ctx context.Context, email string, client *Client) error {
+		response, _, err := client.Orders.Active(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		orders[email] = response.Orders
+		return nil
+	})
+
+	return orders, err
+}
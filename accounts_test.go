@@ -0,0 +1,169 @@
+package tgtg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type countingLimiter struct {
+	waits int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return ctx.Err()
+}
+
+func TestAccounts(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	baseURL, _ := url.Parse(server.URL)
+
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		switch auth := r.Header.Get("Authorization"); auth {
+		case "Bearer access-a":
+			fmt.Fprint(w, `{"orders": [{"order_id": "a-1"}]}`)
+		case "Bearer access-b":
+			fmt.Fprint(w, `{"orders": [{"order_id": "b-1"}, {"order_id": "b-2"}]}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "refreshed", "refresh_token": "refreshed"}`)
+	})
+
+	limiter := &countingLimiter{}
+	accounts := NewAccounts(nil, SetRateLimiter(limiter))
+
+	for _, account := range []struct{ email, accessToken string }{
+		{"a@email.com", "access-a"},
+		{"b@email.com", "access-b"},
+		{"c@email.com", "expired"},
+	} {
+		client, err := accounts.Add(account.email, account.accessToken, "refresh", "1")
+		if err != nil {
+			t.Fatalf("Accounts.Add returned error: %+v", err)
+		}
+		client.BaseURL = baseURL
+	}
+
+	if _, err := accounts.Add("", "", "", ""); err == nil {
+		t.Error("Accounts.Add returned no error for empty email.")
+	}
+
+	if expected := []string{"a@email.com", "b@email.com", "c@email.com"}; !reflect.DeepEqual(accounts.Emails(), expected) {
+		t.Errorf("Accounts.Emails returned: %+v, expected: %+v", accounts.Emails(), expected)
+	}
+
+	orders, err := accounts.ActiveOrders(context.Background())
+	accountsErr, ok := err.(*AccountsError)
+	if !ok {
+		t.Fatalf("Accounts.ActiveOrders returned: %+v, expected: AccountsError", err)
+	}
+	if _, ok := accountsErr.Errors["c@email.com"]; !ok || len(accountsErr.Errors) != 1 {
+		t.Errorf("AccountsError: %+v, expected error of c@email.com only", accountsErr.Errors)
+	}
+	if len(orders["a@email.com"]) != 1 || len(orders["b@email.com"]) != 2 {
+		t.Errorf("Accounts.ActiveOrders returned: %+v, expected 1 order of a and 2 of b", orders)
+	}
+
+	accounts.Remove("a@email.com")
+	if _, ok := accounts.Client("a@email.com"); ok {
+		t.Error("Accounts.Client returned removed account.")
+	}
+
+	if err := accounts.Refresh(context.Background()); err != nil {
+		t.Fatalf("Accounts.Refresh returned error: %+v", err)
+	}
+	for _, email := range accounts.Emails() {
+		client, _ := accounts.Client(email)
+		if client.AccessToken != "refreshed" || client.UserID != "1" {
+			t.Errorf("Client of %s: %+v, expected refreshed tokens", email, client)
+		}
+	}
+
+	if waits := atomic.LoadInt32(&limiter.waits); waits != 5 {
+		t.Errorf("RateLimiter waits: %d, expected: 5", waits)
+	}
+}
+
+func TestAccounts_ConcurrentRefresh(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	baseURL, _ := url.Parse(server.URL)
+
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"orders": []}`)
+	})
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "refreshed", "refresh_token": "refreshed"}`)
+	})
+
+	accounts := NewAccounts(nil)
+	for _, email := range []string{"a@email.com", "b@email.com"} {
+		client, err := accounts.Add(email, "access", "refresh", "1")
+		if err != nil {
+			t.Fatalf("Accounts.Add returned error: %+v", err)
+		}
+		client.BaseURL = baseURL
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := accounts.Refresh(context.Background()); err != nil {
+				t.Errorf("Accounts.Refresh returned error: %+v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := accounts.ActiveOrders(context.Background()); err != nil {
+				t.Errorf("Accounts.ActiveOrders returned error: %+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, email := range accounts.Emails() {
+		client, _ := accounts.Client(email)
+		if client.AccessToken != "refreshed" || client.BaseURL.String() != baseURL.String() {
+			t.Errorf("Client of %s: %+v, expected refreshed tokens", email, client)
+		}
+	}
+}
+
+func TestSetRateLimiter(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if _, err := New(nil, SetRateLimiter(nil)); err == nil {
+		t.Error("New returned no error for nil limiter.")
+	}
+
+	if err := SetRateLimiter(&countingLimiter{})(client); err != nil {
+		t.Fatalf("SetRateLimiter returned error: %+v", err)
+	}
+
+	mux.HandleFunc("/auth/v3/authByEmail", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request sent despite cancelled context.")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := client.Auth.Login(ctx, &LoginRequest{})
+	if err != context.Canceled {
+		t.Errorf("Auth.Login returned: %+v, expected: %+v", err, context.Canceled)
+	}
+}
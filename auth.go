@@ -40,7 +40,7 @@ func (s *AuthServiceOp) Login(ctx context.Context, loginRequest *LoginRequest) (
 	}
 
 	loginResponse := &LoginResponse{}
-	response, err := s.client.Do(ctx, req, loginResponse)
+	response, err := s.client.Do(withServiceMethod(ctx, "Auth.Login"), req, loginResponse)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -64,7 +64,7 @@ func (s *AuthServiceOp) Poll(ctx context.Context, pollRequest *PollRequest) (*Po
 	}
 
 	pollResponse := &PollResponse{}
-	response, err := s.client.Do(ctx, req, pollResponse)
+	response, err := s.client.Do(withServiceMethod(ctx, "Auth.Poll"), req, pollResponse)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,7 +93,7 @@ func (s *AuthServiceOp) Refresh(ctx context.Context, refreshRequest *RefreshToke
 	}
 
 	refreshResponse := &RefreshTokensResponse{}
-	response, err := s.client.Do(ctx, req, refreshResponse)
+	response, err := s.client.Do(withServiceMethod(ctx, "Auth.Refresh"), req, refreshResponse)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -115,7 +115,7 @@ func (s *AuthServiceOp) Signup(ctx context.Context, signupRequest *SignupRequest
 	}
 
 	signupResponse := &SignupResponse{}
-	response, err := s.client.Do(ctx, req, signupResponse)
+	response, err := s.client.Do(withServiceMethod(ctx, "Auth.Signup"), req, signupResponse)
 	if err != nil {
 		return nil, nil, err
 	}
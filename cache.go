@@ -0,0 +1,289 @@
+package tgtg
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores encoded Too Good To Go API responses. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns value stored under key, if present and not expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores value under key for ttl.
+	Set(key string, value []byte, ttl time.Duration)
+
+	// DeletePrefix removes all values with keys starting with prefix.
+	DeletePrefix(prefix string)
+}
+
+// CacheTTLs specifies for how long responses of particular endpoints are cached.
+// Zero TTL disables caching of given endpoint.
+type CacheTTLs struct {
+	ItemsGet  time.Duration
+	ItemsList time.Duration
+}
+
+// CacheHeader is set to "HIT" in responses served from cache.
+const CacheHeader = "X-Cache"
+
+// SetCache is a ClientOption enabling caching of Items.Get and Items.List responses in cache, for given TTLs.
+// Responses are cached by normalized request, concurrent identical requests are sent only once.
+// Cache is invalidated for an item, and all lists, by Items.Favorite. Responses served from cache
+// are returned with synthetic 200 OK *http.Response, having CacheHeader set. If SetKeepRawResponse is used,
+// its body is the cached response encoded with client's Codec.
+func SetCache(cache Cache, ttls CacheTTLs) ClientOption {
+	return func(c *Client) error {
+		if cache == nil {
+			return NewArgumentError("cache", "must not be nil")
+		}
+		c.cache = &responseCache{
+			cache: cache,
+			ttls:  ttls,
+			calls: make(map[string]*cacheCall),
+		}
+		return nil
+	}
+}
+
+// responseCache caches responses in Cache, de-duplicating concurrent identical requests.
+type responseCache struct {
+	cache Cache
+	ttls  CacheTTLs
+
+	mu    sync.Mutex
+	calls map[string]*cacheCall
+}
+
+// cacheCall is an in-flight request, shared by concurrent identical requests.
+type cacheCall struct {
+	// done is closed once the request finishes.
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// errCacheCallAborted is returned to callers waiting for in-flight request which did not finish, e.g. panicked.
+var errCacheCallAborted = errors.New("tgtg: shared in-flight request aborted")
+
+// load decodes response to req cached under key into v using client's codec. If there is none, fetch is called
+// to decode it into v, unless identical request is already in-flight, in which case its result is used.
+// Callers waiting for in-flight request give up once their ctx is done, and send request themselves if the
+// in-flight one failed due to its own context. Only fetching caller receives actual *http.Response.
+func (c *responseCache) load(ctx context.Context, client *Client, req *http.Request, key string, ttl time.Duration, v interface{}, fetch func() (*http.Response, error)) (*http.Response, error) {
+	for {
+		if data, ok := c.cache.Get(key); ok {
+			return client.cachedResponse(req, data), client.codec.Unmarshal(data, v)
+		}
+
+		c.mu.Lock()
+		call, ok := c.calls[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+
+		switch {
+		case isContextError(call.err):
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			continue
+		case call.err != nil:
+			return nil, call.err
+		}
+		return client.cachedResponse(req, call.data), client.codec.Unmarshal(call.data, v)
+	}
+
+	call := &cacheCall{done: make(chan struct{}), err: errCacheCallAborted}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	response, err := fetch()
+	var data []byte
+	if err == nil {
+		data, err = client.codec.Marshal(v)
+	}
+	if err == nil {
+		c.cache.Set(key, data, ttl)
+	}
+	call.data, call.err = data, err
+
+	return response, err
+}
+
+// isContextError reports whether err is caused by cancelled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// cachedResponse returns synthetic 200 OK response to req, served from cache.
+func (c *Client) cachedResponse(req *http.Request, data []byte) *http.Response {
+	var body io.ReadCloser = http.NoBody
+	if c.keepRawResponse {
+		body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{CacheHeader: []string{"HIT"}, "Content-Type": []string{mediaType}},
+		Body:          body,
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
+
+// invalidateItem removes cached responses of given item and all cached lists.
+func (c *responseCache) invalidateItem(itemID string) {
+	c.cache.DeletePrefix(itemsGetCacheKeyPrefix(itemID))
+	c.cache.DeletePrefix("Items.List/")
+}
+
+// itemsGetCacheKeyPrefix returns prefix of cache keys of given item.
+func itemsGetCacheKeyPrefix(itemID string) string {
+	return "Items.Get/" + itemID + "/"
+}
+
+// itemsGetCacheKey returns cache key of item request.
+func itemsGetCacheKey(itemID string, request *GetItemRequest) string {
+	data, _ := json.Marshal(request)
+	return itemsGetCacheKeyPrefix(itemID) + string(data)
+}
+
+// itemsListCacheKey returns cache key of list request, normalized so order of categories does not matter.
+func itemsListCacheKey(request *ListItemsRequest) string {
+	normalized := *request
+	normalized.ItemCategories = sortedCopy(request.ItemCategories)
+	normalized.DietCategories = sortedCopy(request.DietCategories)
+
+	data, _ := json.Marshal(&normalized)
+	return "Items.List/" + string(data)
+}
+
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// LRUCache is an in-memory Cache evicting least recently used values once capacity is exceeded.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+
+	now func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+var _ Cache = &LRUCache{}
+
+// NewLRUCache returns LRUCache holding at most capacity values. Non-positive capacity disables caching,
+// nothing is stored.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get implements Cache interface's method.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set implements Cache interface's method.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: c.now().Add(ttl)})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// DeletePrefix implements Cache interface's method.
+func (c *LRUCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(element)
+		}
+	}
+}
+
+// Len returns number of stored values, including expired ones not evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
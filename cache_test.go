@@ -0,0 +1,271 @@
+package tgtg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)
+	cache := NewLRUCache(2)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("a"), time.Minute)
+	cache.Set("b", []byte("b"), time.Hour)
+	cache.Get("a")
+	cache.Set("c", []byte("c"), time.Hour)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("LRUCache.Get returned least recently used value, expected it evicted.")
+	}
+	if value, ok := cache.Get("a"); !ok || string(value) != "a" {
+		t.Errorf("LRUCache.Get returned: %+v, %+v, expected: a, true", string(value), ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("LRUCache.Get returned expired value.")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("LRUCache.Len returned: %d, expected: 1", cache.Len())
+	}
+
+	cache.Set("prefix/1", []byte("1"), time.Hour)
+	cache.DeletePrefix("prefix/")
+	if _, ok := cache.Get("prefix/1"); ok {
+		t.Error("LRUCache.Get returned value deleted by prefix.")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("LRUCache.DeletePrefix deleted value not matching prefix.")
+	}
+}
+
+func TestLRUCache_NoCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		cache := NewLRUCache(capacity)
+		cache.Set("a", []byte("a"), time.Hour)
+
+		if _, ok := cache.Get("a"); ok || cache.Len() != 0 {
+			t.Errorf("LRUCache of capacity %d stored value, expected caching disabled.", capacity)
+		}
+	}
+}
+
+func TestSetCache(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	if _, err := New(nil, SetCache(nil, CacheTTLs{})); err == nil {
+		t.Error("New returned no error for nil cache.")
+	}
+
+	err := SetCache(NewLRUCache(10), CacheTTLs{ItemsGet: time.Hour, ItemsList: time.Hour})(client)
+	if err != nil {
+		t.Fatalf("SetCache returned error: %+v", err)
+	}
+	client.SetAuthContext("access_token", "refresh_token", "1")
+
+	var gets, lists int32
+	release := make(chan struct{})
+	mux.HandleFunc("/item/v7/1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&gets, 1)
+		<-release
+		fmt.Fprint(w, `{"item": {"item_id": "1"}, "favorite": false}`)
+	})
+	mux.HandleFunc("/item/v7/1/setFavorite", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/item/v7/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lists, 1)
+		fmt.Fprint(w, `{"items": [{"display_name": "name"}]}`)
+	})
+
+	// Concurrent identical requests are sent once.
+	var wg sync.WaitGroup
+	results := make([]*GetItemResponse, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item, _, err := client.Items.Get(context.Background(), &GetItemRequest{}, "1")
+			if err != nil {
+				t.Errorf("Items.Get returned error: %+v", err)
+			}
+			results[i] = item
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, item := range results {
+		if item == nil || item.Item.ItemID != "1" {
+			t.Errorf("Items.Get returned: %+v, expected item 1", item)
+		}
+	}
+	if results[0] == results[1] {
+		t.Error("Items.Get returned shared response value.")
+	}
+
+	// Cached request is not sent, response is synthetic.
+	item, response, err := client.Items.Get(context.Background(), &GetItemRequest{UserID: "1"}, "1")
+	if err != nil || item.Item.ItemID != "1" || response == nil || response.StatusCode != http.StatusOK || response.Header.Get(CacheHeader) != "HIT" {
+		t.Errorf("Items.Get returned: %+v, %+v, %+v, expected cached item 1", item, response, err)
+	}
+	if atomic.LoadInt32(&gets) != 1 {
+		t.Errorf("Items.Get requests sent: %d, expected: 1", gets)
+	}
+
+	// Order of categories does not matter.
+	list := func(categories ...string) {
+		request := &ListItemsRequest{Radius: 1, PageSize: 1, Page: 1, Origin: &Origin{}, ItemCategories: categories}
+		response, _, err := client.Items.List(context.Background(), request)
+		if err != nil || len(response.Items) != 1 {
+			t.Errorf("Items.List returned: %+v, %+v, expected one item", response, err)
+		}
+	}
+	list("MEAL", "GROCERIES")
+	list("GROCERIES", "MEAL")
+	if atomic.LoadInt32(&lists) != 1 {
+		t.Errorf("Items.List requests sent: %d, expected: 1", lists)
+	}
+
+	// Favorite invalidates item and lists.
+	_, err = client.Items.Favorite(context.Background(), &FavoriteItemRequest{IsFavorite: true}, "1")
+	if err != nil {
+		t.Fatalf("Items.Favorite returned error: %+v", err)
+	}
+	_, _, _ = client.Items.Get(context.Background(), &GetItemRequest{}, "1")
+	list("MEAL", "GROCERIES")
+	if atomic.LoadInt32(&gets) != 2 || atomic.LoadInt32(&lists) != 2 {
+		t.Errorf("Requests sent after Items.Favorite: %d gets, %d lists, expected: 2, 2", gets, lists)
+	}
+}
+
+func TestResponseCache_load(t *testing.T) {
+	client := NewClient(nil)
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+
+	// newCache returns cache with in-flight request under key "key", which blocks until released and then
+	// finishes with given error, or panics if panics is set.
+	newCache := func(err error, panics bool) (*responseCache, chan struct{}, chan struct{}) {
+		cache := &responseCache{cache: NewLRUCache(10), calls: make(map[string]*cacheCall)}
+		started, release, finished := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(finished)
+			defer func() { recover() }()
+
+			var v map[string]string
+			cache.load(context.Background(), client, req, "key", time.Hour, &v, func() (*http.Response, error) {
+				close(started)
+				<-release
+				if panics {
+					panic("fetch")
+				}
+				if err != nil {
+					return nil, err
+				}
+				v = map[string]string{"leader": "value"}
+				return &http.Response{}, nil
+			})
+		}()
+		<-started
+		return cache, release, finished
+	}
+
+	testCases := []struct {
+		title      string
+		leaderErr  error
+		panics     bool
+		waiterCtx  func() (context.Context, context.CancelFunc)
+		release    bool
+		expected   map[string]string
+		expectedFn func(error) bool
+	}{
+		{
+			title:     "shares leader result",
+			release:   true,
+			waiterCtx: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expected:  map[string]string{"leader": "value"},
+		},
+		{
+			title: "waiter deadline",
+			waiterCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			expectedFn: func(err error) bool { return errors.Is(err, context.DeadlineExceeded) },
+		},
+		{
+			title:     "leader cancelled",
+			leaderErr: context.Canceled,
+			release:   true,
+			waiterCtx: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expected:  map[string]string{"waiter": "value"},
+		},
+		{
+			title:      "leader failed",
+			leaderErr:  errors.New("failed"),
+			release:    true,
+			waiterCtx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expectedFn: func(err error) bool { return err != nil && err.Error() == "failed" },
+		},
+		{
+			title:      "leader panicked",
+			panics:     true,
+			release:    true,
+			waiterCtx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expectedFn: func(err error) bool { return err == errCacheCallAborted },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			cache, release, finished := newCache(tc.leaderErr, tc.panics)
+			defer func() { <-finished }()
+			defer close(release)
+
+			ctx, cancel := tc.waiterCtx()
+			defer cancel()
+
+			done := make(chan struct{})
+			var (
+				v   map[string]string
+				err error
+			)
+			go func() {
+				defer close(done)
+				_, err = cache.load(ctx, client, req, "key", time.Hour, &v, func() (*http.Response, error) {
+					v = map[string]string{"waiter": "value"}
+					return &http.Response{}, nil
+				})
+			}()
+
+			if tc.release {
+				// Let waiter start waiting for the leader.
+				time.Sleep(10 * time.Millisecond)
+				release <- struct{}{}
+			}
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("responseCache.load did not return.")
+			}
+
+			if tc.expectedFn != nil {
+				if !tc.expectedFn(err) {
+					t.Errorf("responseCache.load returned error: %+v", err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(v, tc.expected) {
+				t.Errorf("responseCache.load returned: %+v, %+v, expected: %+v", v, err, tc.expected)
+			}
+		})
+	}
+}
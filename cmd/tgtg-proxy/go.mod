@@ -0,0 +1,9 @@
+module github.com/filippalach/tgt-go/cmd/tgtg-proxy
+
+go 1.17
+
+require github.com/filippalach/tgt-go v0.0.0-00010101000000-000000000000
+
+require github.com/logrusorgru/aurora v2.0.3+incompatible // indirect
+
+replace github.com/filippalach/tgt-go => ../..
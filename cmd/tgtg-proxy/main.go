@@ -0,0 +1,92 @@
+// Command tgtg-proxy is a local HTTP server exposing Too Good To Go API as REST/JSON endpoints,
+// called on behalf of a single authenticated user. It allows using the session from any language.
+//
+// Session tokens are persisted to a file and refreshed before they expire. To create the session,
+// run the proxy with -login flag and confirm login using the link sent in email:
+//
+//	tgtg-proxy -login you@example.com -api-key secret
+//
+// Local callers authenticate with the API key, sent in X-API-Key header or as a bearer token:
+//
+//	curl -H "X-API-Key: secret" "localhost:8080/v1/items?lat=52.23&lng=21.01&radius=5"
+//
+// OpenAPI description of the endpoints is served at /openapi.json.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/filippalach/tgt-go/internal/session"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "address to listen on")
+	sessionPath := flag.String("session", "tgtg-session.json", "file persisting session tokens")
+	apiKey := flag.String("api-key", os.Getenv("TGTG_PROXY_API_KEY"), "API key of local callers, TGTG_PROXY_API_KEY by default")
+	email := flag.String("login", "", "log in with email before serving, replacing persisted session")
+	deviceType := flag.String("device-type", "ANDROID", "device type used to log in, ANDROID or IOS")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "tgtg-proxy: ", log.LstdFlags)
+	if err := run(*addr, *sessionPath, *apiKey, *email, *deviceType, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(addr, sessionPath, apiKey, email, deviceType string, logger *log.Logger) error {
+	if apiKey == "" {
+		return errors.New("API key is required, set -api-key flag or TGTG_PROXY_API_KEY environment variable")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s, err := session.Load(tgtg.NewClient(nil), sessionPath)
+	if err != nil {
+		return err
+	}
+
+	if email != "" {
+		logger.Printf("logging in as %s, confirm login using the link sent in email", email)
+		if err := s.Login(ctx, email, deviceType, 5*time.Second); err != nil {
+			return err
+		}
+		logger.Printf("logged in, session saved to %s", sessionPath)
+	}
+	if !s.Authenticated() {
+		return errors.New("session is not authenticated, log in using -login flag")
+	}
+
+	go s.Run(ctx, logger)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           newServer(s, apiKey, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Printf("listening on %s", addr)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/filippalach/tgt-go/internal/session"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// apiKeyHeader is header carrying API key of local callers, alternatively to "Authorization: Bearer <key>".
+const apiKeyHeader = "X-API-Key"
+
+// server exposes Too Good To Go API, called on behalf of session's user, as REST/JSON endpoints.
+type server struct {
+	session *session.Session
+	apiKey  string
+	logger  *log.Logger
+}
+
+// newServer returns HTTP handler of proxy endpoints. All endpoints but OpenAPI description require apiKey.
+func newServer(session *session.Session, apiKey string, logger *log.Logger) http.Handler {
+	s := &server{session: session, apiKey: apiKey, logger: logger}
+
+	api := http.NewServeMux()
+	api.HandleFunc("/v1/items", s.method(http.MethodGet, s.listItems))
+	api.HandleFunc("/v1/items/", s.item)
+	api.HandleFunc("/v1/favorites", s.method(http.MethodGet, s.listFavorites))
+	api.HandleFunc("/v1/stores", s.method(http.MethodGet, s.listStores))
+	api.HandleFunc("/v1/orders/active", s.method(http.MethodGet, s.activeOrders))
+	api.HandleFunc("/v1/orders/inactive", s.method(http.MethodGet, s.inactiveOrders))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", s.method(http.MethodGet, s.openAPI))
+	mux.Handle("/", s.authenticate(api))
+
+	return s.logRequests(mux)
+}
+
+// method restricts handler to single HTTP method.
+func (s *server) method(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// authenticate rejects requests without valid API key.
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(key), []byte(s.apiKey)) != 1 {
+			s.writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder records status code written by handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs method, path, status and latency of every request.
+func (s *server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		s.logger.Printf("%s %s %d %s", r.Method, r.URL.Path, recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+func (s *server) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPI)
+}
+
+// item handles "/v1/items/{id}" and "/v1/items/{id}/favorite".
+func (s *server) item(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/items/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		s.method(http.MethodGet, func(w http.ResponseWriter, r *http.Request) { s.getItem(w, r, parts[0]) })(w, r)
+	case len(parts) == 2 && parts[0] != "" && parts[1] == "favorite":
+		s.favorite(w, r, parts[0])
+	default:
+		s.writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *server) listItems(w http.ResponseWriter, r *http.Request) {
+	request, err := listItemsRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var response *tgtg.ListItemsResponse
+	err = s.call(r.Context(), func(client *tgtg.Client) (err error) {
+		response, _, err = client.Items.List(r.Context(), request)
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+func (s *server) listFavorites(w http.ResponseWriter, r *http.Request) {
+	request, err := listItemsRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.FavoritesOnly = true
+
+	var response *tgtg.ListItemsResponse
+	err = s.call(r.Context(), func(client *tgtg.Client) (err error) {
+		response, _, err = client.Items.List(r.Context(), request)
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// storeResponse represents store with its items, as returned by "/v1/stores".
+type storeResponse struct {
+	Store tgtg.Store   `json:"store"`
+	Items []tgtg.Items `json:"items"`
+}
+
+// listStores lists items as in "/v1/items", grouped by store, ordered by distance.
+func (s *server) listStores(w http.ResponseWriter, r *http.Request) {
+	request, err := listItemsRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var response *tgtg.ListItemsResponse
+	err = s.call(r.Context(), func(client *tgtg.Client) (err error) {
+		response, _, err = client.Items.List(r.Context(), request)
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+
+	stores := []*storeResponse{}
+	byID := map[string]*storeResponse{}
+	for _, items := range response.Items {
+		store, ok := byID[items.Store.StoreID]
+		if !ok {
+			store = &storeResponse{Store: items.Store}
+			byID[items.Store.StoreID] = store
+			stores = append(stores, store)
+		}
+		store.Items = append(store.Items, items)
+	}
+	sort.SliceStable(stores, func(i, j int) bool { return stores[i].Store.Distance < stores[j].Store.Distance })
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{"stores": stores})
+}
+
+func (s *server) getItem(w http.ResponseWriter, r *http.Request, itemID string) {
+	var response *tgtg.GetItemResponse
+	err := s.call(r.Context(), func(client *tgtg.Client) (err error) {
+		response, _, err = client.Items.Get(r.Context(), &tgtg.GetItemRequest{}, itemID)
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// favorite sets item as favorite on PUT, and unsets it on DELETE.
+func (s *server) favorite(w http.ResponseWriter, r *http.Request, itemID string) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		w.Header().Set("Allow", "PUT, DELETE")
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	request := &tgtg.FavoriteItemRequest{IsFavorite: r.Method == http.MethodPut}
+	err := s.call(r.Context(), func(client *tgtg.Client) error {
+		_, err := client.Items.Favorite(r.Context(), request, itemID)
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) activeOrders(w http.ResponseWriter, r *http.Request) {
+	var response *tgtg.OrdersResponse
+	err := s.call(r.Context(), func(client *tgtg.Client) (err error) {
+		response, _, err = client.Orders.Active(r.Context(), nil)
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+func (s *server) inactiveOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, err := intParam(query.Get("page"), 0)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "page: "+err.Error())
+		return
+	}
+	size, err := intParam(query.Get("size"), 20)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "size: "+err.Error())
+		return
+	}
+
+	var response *tgtg.OrdersResponse
+	err = s.call(r.Context(), func(client *tgtg.Client) (err error) {
+		response, _, err = client.Orders.Inactive(r.Context(), &tgtg.InactiveOrdersRequest{Paging: tgtg.Paging{Page: page, Size: size}})
+		return err
+	})
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// errNotAuthenticated is returned when session holds no tokens.
+var errNotAuthenticated = errors.New("proxy session is not authenticated")
+
+// call calls fn with session's client. If Too Good To Go API rejects access token,
+// session is refreshed, unless it already was by a concurrent call, and fn called once again.
+func (s *server) call(ctx context.Context, fn func(*tgtg.Client) error) error {
+	if !s.session.Authenticated() {
+		return errNotAuthenticated
+	}
+
+	client := s.session.Client()
+	err := fn(client)
+
+	var errorResponse *tgtg.ErrorResponse
+	if errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusUnauthorized {
+		if refreshErr := s.session.RefreshRejected(ctx, client.AccessToken); refreshErr != nil {
+			s.logger.Printf("refreshing tokens failed: %v", refreshErr)
+			return err
+		}
+		err = fn(s.session.Client())
+	}
+	return err
+}
+
+// listItemsRequest builds ListItemsRequest from query parameters.
+func listItemsRequest(r *http.Request) (*tgtg.ListItemsRequest, error) {
+	query := r.URL.Query()
+
+	latitude, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil {
+		return nil, errors.New("lat: must be a number")
+	}
+	longitude, err := strconv.ParseFloat(query.Get("lng"), 64)
+	if err != nil {
+		return nil, errors.New("lng: must be a number")
+	}
+	radius, err := intParam(query.Get("radius"), 1)
+	if err != nil {
+		return nil, errors.New("radius: " + err.Error())
+	}
+	page, err := intParam(query.Get("page"), 1)
+	if err != nil {
+		return nil, errors.New("page: " + err.Error())
+	}
+	pageSize, err := intParam(query.Get("page_size"), 20)
+	if err != nil {
+		return nil, errors.New("page_size: " + err.Error())
+	}
+
+	q := tgtg.NewListItemsQuery(tgtg.Origin{Latitude: latitude, Longitude: longitude}, radius).
+		Page(page, pageSize).
+		Search(query.Get("search"))
+
+	for _, category := range splitParam(query.Get("item_categories")) {
+		q.ItemCategories(tgtg.ItemCategory(category))
+	}
+	for _, category := range splitParam(query.Get("diet_categories")) {
+		q.DietCategories(tgtg.DietCategory(category))
+	}
+
+	// Either bound may be given alone, the other one is left unset as zero time.
+	var earliest, latest time.Time
+	if value := query.Get("pickup_earliest"); value != "" {
+		if earliest, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, errors.New("pickup_earliest: must be RFC 3339 time")
+		}
+	}
+	if value := query.Get("pickup_latest"); value != "" {
+		if latest, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, errors.New("pickup_latest: must be RFC 3339 time")
+		}
+	}
+	q.PickupBetween(earliest, latest)
+
+	flags := []struct {
+		name string
+		set  func() *tgtg.ListItemsQuery
+	}{
+		{name: "with_stock_only", set: q.WithStockOnly},
+		{name: "we_care_only", set: q.WeCareOnly},
+		{name: "hidden_only", set: q.HiddenOnly},
+		{name: "discover", set: q.Discover},
+	}
+	for _, flag := range flags {
+		if value := query.Get(flag.name); value != "" {
+			set, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errors.New(flag.name + ": must be a boolean")
+			}
+			if set {
+				flag.set()
+			}
+		}
+	}
+
+	return q.Build()
+}
+
+// intParam parses integer query parameter, returning fallback if it is empty.
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("must be an integer")
+	}
+	return n, nil
+}
+
+// splitParam splits comma separated query parameter.
+func splitParam(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, strings.ToUpper(v))
+		}
+	}
+	return values
+}
+
+// errorBody represents body of error responses.
+type errorBody struct {
+	Error string `json:"error"`
+
+	// UpstreamStatus and UpstreamErrors describe rejection by Too Good To Go API.
+	UpstreamStatus int          `json:"upstream_status,omitempty"`
+	UpstreamErrors []tgtg.Error `json:"upstream_errors,omitempty"`
+}
+
+// writeAPIError writes error returned by the library, mapping it to HTTP status.
+// Errors returned by Too Good To Go API are logged in full, but only their status and codes are passed to caller.
+func (s *server) writeAPIError(w http.ResponseWriter, err error) {
+	var argumentError *tgtg.ArgumentError
+	var errorResponse *tgtg.ErrorResponse
+
+	switch {
+	case errors.Is(err, errNotAuthenticated):
+		s.writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.As(err, &argumentError):
+		s.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.As(err, &errorResponse) && errorResponse.Response != nil:
+		s.logger.Printf("Too Good To Go API error: %v", err)
+
+		upstream := errorResponse.Response.StatusCode
+		status := upstream
+		// Rejected credentials are proxy's problem, not caller's.
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			status = http.StatusBadGateway
+		}
+		s.writeJSON(w, status, errorBody{
+			Error:          fmt.Sprintf("Too Good To Go API responded with %d %s", upstream, http.StatusText(upstream)),
+			UpstreamStatus: upstream,
+			UpstreamErrors: errorResponse.Errors,
+		})
+	default:
+		s.writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
+
+func (s *server) writeError(w http.ResponseWriter, status int, message string) {
+	s.writeJSON(w, status, errorBody{Error: message})
+}
+
+func (s *server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Printf("writing response failed: %v", err)
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/filippalach/tgt-go/internal/session"
+)
+
+const testAPIKey = "secret"
+
+// setupServer returns proxy handler with authenticated session calling returned test upstream mux.
+func setupServer(t *testing.T) (http.Handler, *http.ServeMux, *session.Session) {
+	mux := http.NewServeMux()
+	upstream := httptest.NewServer(mux)
+	t.Cleanup(upstream.Close)
+
+	client := tgtg.NewClient(nil)
+	client.BaseURL, _ = url.Parse(upstream.URL + "/")
+
+	s, err := session.Load(client, filepath.Join(t.TempDir(), "session.json"))
+	if err != nil {
+		t.Fatalf("session.Load returned error: %+v", err)
+	}
+	if err := s.Set(client.WithAuth("access", "refresh", "1"), time.Hour); err != nil {
+		t.Fatalf("Session.Set returned error: %+v", err)
+	}
+
+	return newServer(s, testAPIKey, log.New(ioutil.Discard, "", 0)), mux, s
+}
+
+func serve(handler http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	for k, values := range header {
+		for _, v := range values {
+			request.Header.Add(k, v)
+		}
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+var authorized = http.Header{apiKeyHeader: {testAPIKey}}
+
+func TestServer_Authenticate(t *testing.T) {
+	handler, mux, _ := setupServer(t)
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"orders": []}`)
+	})
+
+	testCases := []struct {
+		title    string
+		header   http.Header
+		expected int
+	}{
+		{title: "no key", expected: http.StatusUnauthorized},
+		{title: "invalid key", header: http.Header{apiKeyHeader: {"invalid"}}, expected: http.StatusUnauthorized},
+		{title: "key header", header: authorized, expected: http.StatusOK},
+		{title: "bearer token", header: http.Header{"Authorization": {"Bearer " + testAPIKey}}, expected: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := serve(handler, http.MethodGet, "/v1/orders/active", tc.header).Code; actual != tc.expected {
+				t.Errorf("Status: %d, expected: %d", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestServer_ListItems(t *testing.T) {
+	handler, mux, _ := setupServer(t)
+
+	var upstream tgtg.ListItemsRequest
+	mux.HandleFunc("/item/v7/", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&upstream); err != nil {
+			t.Errorf("Decode json: %+v", err)
+		}
+		if actual := r.Header.Get("Authorization"); actual != "Bearer access" {
+			t.Errorf("Authorization header: %+v, expected: Bearer access", actual)
+		}
+		fmt.Fprint(w, `{"items": [{"display_name": "name-1"}]}`)
+	})
+
+	response := serve(handler, http.MethodGet, "/v1/items?lat=52.23&lng=21.01&radius=5&item_categories=meal,baked_goods&with_stock_only=true", authorized)
+	if response.Code != http.StatusOK {
+		t.Fatalf("Status: %d, expected: %d, body: %s", response.Code, http.StatusOK, response.Body)
+	}
+
+	expected := tgtg.ListItemsRequest{
+		PageSize:       20,
+		Page:           1,
+		UserID:         "1",
+		Radius:         5,
+		Origin:         &tgtg.Origin{Latitude: 52.23, Longitude: 21.01},
+		ItemCategories: []string{"MEAL", "BAKED_GOODS"},
+		WithStockOnly:  true,
+	}
+	if !reflect.DeepEqual(upstream, expected) {
+		t.Errorf("Upstream request: %+v, expected: %+v", upstream, expected)
+	}
+
+	var actual tgtg.ListItemsResponse
+	if err := json.Unmarshal(response.Body.Bytes(), &actual); err != nil || len(actual.Items) != 1 {
+		t.Errorf("Response: %s, expected single item", response.Body)
+	}
+}
+
+func TestServer_BadRequest(t *testing.T) {
+	handler, _, _ := setupServer(t)
+
+	testCases := []struct {
+		title  string
+		method string
+		target string
+		status int
+	}{
+		{title: "missing origin", method: http.MethodGet, target: "/v1/items", status: http.StatusBadRequest},
+		{title: "invalid radius", method: http.MethodGet, target: "/v1/items?lat=1&lng=1&radius=1000", status: http.StatusBadRequest},
+		{title: "invalid category", method: http.MethodGet, target: "/v1/favorites?lat=1&lng=1&item_categories=pizza", status: http.StatusBadRequest},
+		{title: "invalid page", method: http.MethodGet, target: "/v1/orders/inactive?page=first", status: http.StatusBadRequest},
+		{title: "wrong method", method: http.MethodPost, target: "/v1/orders/active", status: http.StatusMethodNotAllowed},
+		{title: "unknown path", method: http.MethodGet, target: "/v1/items/1/unknown", status: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			response := serve(handler, tc.method, tc.target, authorized)
+			if response.Code != tc.status {
+				t.Errorf("Status: %d, expected: %d", response.Code, tc.status)
+			}
+
+			var body errorBody
+			if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil || body.Error == "" {
+				t.Errorf("Body: %s, expected JSON error", response.Body)
+			}
+		})
+	}
+}
+
+func TestListItemsRequest_Pickup(t *testing.T) {
+	testCases := []struct {
+		title    string
+		query    string
+		earliest string
+		latest   string
+		invalid  bool
+	}{
+		{title: "earliest only", query: "&pickup_earliest=2021-12-01T18:00:00Z", earliest: "2021-12-01T18:00:00Z"},
+		{title: "latest only", query: "&pickup_latest=2021-12-01T20:00:00%2B01:00", latest: "2021-12-01T19:00:00Z"},
+		{title: "both", query: "&pickup_earliest=2021-12-01T18:00:00Z&pickup_latest=2021-12-01T19:00:00Z", earliest: "2021-12-01T18:00:00Z", latest: "2021-12-01T19:00:00Z"},
+		{title: "none"},
+		{title: "invalid earliest", query: "&pickup_earliest=18:00", invalid: true},
+		{title: "invalid latest", query: "&pickup_latest=tomorrow", invalid: true},
+		{title: "earliest after latest", query: "&pickup_earliest=2021-12-01T19:00:00Z&pickup_latest=2021-12-01T18:00:00Z", invalid: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			request, err := listItemsRequest(httptest.NewRequest(http.MethodGet, "/v1/items?lat=1&lng=1"+tc.query, nil))
+			if tc.invalid {
+				if err == nil {
+					t.Errorf("listItemsRequest returned: %+v, expected error", request)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("listItemsRequest returned error: %+v", err)
+			}
+			if request.PickupEarliest != tc.earliest || request.PickupLatest != tc.latest {
+				t.Errorf("listItemsRequest returned pickup: %q - %q, expected: %q - %q", request.PickupEarliest, request.PickupLatest, tc.earliest, tc.latest)
+			}
+		})
+	}
+}
+
+func TestServer_Favorite(t *testing.T) {
+	handler, mux, _ := setupServer(t)
+
+	var upstream []tgtg.FavoriteItemRequest
+	mux.HandleFunc("/item/v7/1/setFavorite", func(w http.ResponseWriter, r *http.Request) {
+		request := tgtg.FavoriteItemRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Decode json: %+v", err)
+		}
+		upstream = append(upstream, request)
+	})
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		if actual := serve(handler, method, "/v1/items/1/favorite", authorized).Code; actual != http.StatusNoContent {
+			t.Errorf("%s status: %d, expected: %d", method, actual, http.StatusNoContent)
+		}
+	}
+
+	expected := []tgtg.FavoriteItemRequest{{IsFavorite: true}, {IsFavorite: false}}
+	if !reflect.DeepEqual(upstream, expected) {
+		t.Errorf("Upstream requests: %+v, expected: %+v", upstream, expected)
+	}
+}
+
+func TestServer_ListStores(t *testing.T) {
+	handler, mux, _ := setupServer(t)
+	mux.HandleFunc("/item/v7/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [
+			{"item": {"item_id": "1"}, "store": {"store_id": "far", "distance": 2}},
+			{"item": {"item_id": "2"}, "store": {"store_id": "near", "distance": 1}},
+			{"item": {"item_id": "3"}, "store": {"store_id": "far", "distance": 2}}
+		]}`)
+	})
+
+	response := serve(handler, http.MethodGet, "/v1/stores?lat=1&lng=1", authorized)
+	if response.Code != http.StatusOK {
+		t.Fatalf("Status: %d, expected: %d", response.Code, http.StatusOK)
+	}
+
+	var actual struct {
+		Stores []storeResponse `json:"stores"`
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), &actual); err != nil {
+		t.Fatalf("Decode json: %+v", err)
+	}
+
+	var stores []string
+	for _, store := range actual.Stores {
+		stores = append(stores, fmt.Sprintf("%s:%d", store.Store.StoreID, len(store.Items)))
+	}
+	if expected := []string{"near:1", "far:2"}; !reflect.DeepEqual(stores, expected) {
+		t.Errorf("Stores: %+v, expected: %+v", stores, expected)
+	}
+}
+
+func TestServer_RefreshOnUnauthorized(t *testing.T) {
+	handler, mux, s := setupServer(t)
+
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new_access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"orders": [{"order_id": "1"}]}`)
+	})
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "new_access", "refresh_token": "new_refresh"}`)
+	})
+
+	response := serve(handler, http.MethodGet, "/v1/orders/active", authorized)
+	if response.Code != http.StatusOK {
+		t.Fatalf("Status: %d, expected: %d, body: %s", response.Code, http.StatusOK, response.Body)
+	}
+	if actual := s.Client().AccessToken; actual != "new_access" {
+		t.Errorf("Session access token: %+v, expected: new_access", actual)
+	}
+}
+
+func TestServer_ConcurrentRefresh(t *testing.T) {
+	handler, mux, _ := setupServer(t)
+
+	var refreshes int32
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new_access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"orders": []}`)
+	})
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"access_token": "new_access", "refresh_token": "new_refresh"}`)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if response := serve(handler, http.MethodGet, "/v1/orders/active", authorized); response.Code != http.StatusOK {
+				t.Errorf("Status: %d, expected: %d, body: %s", response.Code, http.StatusOK, response.Body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if actual := atomic.LoadInt32(&refreshes); actual != 1 {
+		t.Errorf("Refresh requests sent: %d, expected: 1", actual)
+	}
+}
+
+func TestServer_UpstreamError(t *testing.T) {
+	handler, mux, _ := setupServer(t)
+	mux.HandleFunc("/item/v7/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors": [{"code": "NOT_FOUND", "message": "item not found"}]}`)
+	})
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	testCases := []struct {
+		title    string
+		target   string
+		status   int
+		expected errorBody
+	}{
+		{
+			title:  "passed through",
+			target: "/v1/items/1",
+			status: http.StatusNotFound,
+			expected: errorBody{
+				Error:          "Too Good To Go API responded with 404 Not Found",
+				UpstreamStatus: http.StatusNotFound,
+				UpstreamErrors: []tgtg.Error{{Code: "NOT_FOUND", Message: "item not found"}},
+			},
+		},
+		{
+			title:  "rejected credentials",
+			target: "/v1/orders/active",
+			status: http.StatusBadGateway,
+			expected: errorBody{
+				Error:          "Too Good To Go API responded with 403 Forbidden",
+				UpstreamStatus: http.StatusForbidden,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			response := serve(handler, http.MethodGet, tc.target, authorized)
+			if response.Code != tc.status {
+				t.Errorf("Status: %d, expected: %d", response.Code, tc.status)
+			}
+
+			var body errorBody
+			if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Body: %s, expected JSON error", response.Body)
+			}
+			if !reflect.DeepEqual(body, tc.expected) {
+				t.Errorf("Body: %+v, expected: %+v", body, tc.expected)
+			}
+		})
+	}
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	handler, _, _ := setupServer(t)
+
+	response := serve(handler, http.MethodGet, "/openapi.json", nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("Status: %d, expected: %d", response.Code, http.StatusOK)
+	}
+
+	var document struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), &document); err != nil {
+		t.Fatalf("Decode json: %+v", err)
+	}
+
+	var actual []string
+	for path, operations := range document.Paths {
+		for method := range operations {
+			actual = append(actual, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(actual)
+
+	expected := []string{
+		"DELETE /v1/items/{id}/favorite",
+		"GET /openapi.json",
+		"GET /v1/favorites",
+		"GET /v1/items",
+		"GET /v1/items/{id}",
+		"GET /v1/orders/active",
+		"GET /v1/orders/inactive",
+		"GET /v1/stores",
+		"PUT /v1/items/{id}/favorite",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("OpenAPI operations: %+v, expected: %+v", actual, expected)
+	}
+}
@@ -0,0 +1,79 @@
+// Command tgtg-tui is an interactive terminal UI for browsing Too Good To Go bags nearby and active orders.
+//
+// It uses session file of tgtg-proxy command, create it with:
+//
+//	tgtg-proxy -login you@example.com -api-key secret
+//
+// or log in directly with -login flag. Then browse bags around given location:
+//
+//	tgtg-tui -lat 52.23 -lng 21.01 -radius 5
+//
+// Items table refreshes periodically and can be sorted and filtered. Press enter to see item details,
+// f to un/set item as favorite, tab to switch to orders.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/filippalach/tgt-go/internal/session"
+)
+
+func main() {
+	sessionPath := flag.String("session", "tgtg-session.json", "file persisting session tokens, shared with tgtg-proxy")
+	email := flag.String("login", "", "log in with email before starting, replacing persisted session")
+	deviceType := flag.String("device-type", "ANDROID", "device type used to log in, ANDROID or IOS")
+	latitude := flag.Float64("lat", 0, "latitude of search origin")
+	longitude := flag.Float64("lng", 0, "longitude of search origin")
+	radius := flag.Int("radius", 5, "search radius in kilometers")
+	interval := flag.Duration("refresh", time.Minute, "items refresh interval")
+	flag.Parse()
+
+	if err := run(*sessionPath, *email, *deviceType, tgtg.Origin{Latitude: *latitude, Longitude: *longitude}, *radius, *interval); err != nil {
+		fmt.Fprintln(os.Stderr, "tgtg-tui:", err)
+		os.Exit(1)
+	}
+}
+
+func run(sessionPath, email, deviceType string, origin tgtg.Origin, radius int, interval time.Duration) error {
+	if origin == (tgtg.Origin{}) {
+		return errors.New("search origin is required, set -lat and -lng flags")
+	}
+
+	request, err := tgtg.NewListItemsQuery(origin, radius).Page(1, tgtg.MaxPageSize).Build()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := session.Load(tgtg.NewClient(nil), sessionPath)
+	if err != nil {
+		return err
+	}
+	if email != "" {
+		fmt.Printf("Logging in as %s, confirm login using the link sent in email...\n", email)
+		if err := s.Login(ctx, email, deviceType, 5*time.Second); err != nil {
+			return err
+		}
+	}
+	if !s.Authenticated() {
+		return errors.New("session is not authenticated, log in using -login flag")
+	}
+
+	// Terminal belongs to the UI, refresh errors are shown when requests fail.
+	go s.Run(ctx, log.New(ioutil.Discard, "", 0))
+
+	program := tea.NewProgram(newModel(s.Client, request, interval), tea.WithAltScreen())
+	_, err = program.Run()
+	return err
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/filippalach/tgt-go/filter"
+)
+
+// requestTimeout limits duration of every Too Good To Go API call.
+const requestTimeout = 30 * time.Second
+
+type tab int
+
+const (
+	itemsTab tab = iota
+	ordersTab
+)
+
+// sortOrder is a named sorting of items table.
+type sortOrder struct {
+	name string
+	keys []filter.Key
+}
+
+var sortOrders = []sortOrder{
+	{name: "distance", keys: []filter.Key{filter.ByDistance}},
+	{name: "price", keys: []filter.Key{filter.ByPrice, filter.ByDistance}},
+	{name: "discount", keys: []filter.Key{filter.Desc(filter.ByDiscount), filter.ByDistance}},
+	{name: "rating", keys: []filter.Key{filter.Desc(filter.ByRating), filter.ByDistance}},
+	{name: "available", keys: []filter.Key{filter.Desc(filter.ByAvailable), filter.ByDistance}},
+	{name: "pickup", keys: []filter.Key{filter.ByPickupStart, filter.ByDistance}},
+}
+
+// clientFunc returns client to call Too Good To Go API with. It is called for every request,
+// so that refreshed session is picked up.
+type clientFunc func() *tgtg.Client
+
+type itemsMsg struct {
+	items []tgtg.Items
+	err   error
+}
+
+type itemMsg struct {
+	item *tgtg.GetItemResponse
+	err  error
+}
+
+type ordersMsg struct {
+	orders []tgtg.Order
+	err    error
+}
+
+type favoriteMsg struct {
+	itemID   string
+	favorite bool
+	err      error
+}
+
+type tickMsg time.Time
+
+// model is bubbletea model of the terminal UI.
+type model struct {
+	client   clientFunc
+	request  *tgtg.ListItemsRequest
+	interval time.Duration
+
+	tab    tab
+	width  int
+	height int
+	status string
+
+	// items holds all listed items, rows those matching filters, in selected sort order.
+	items     []tgtg.Items
+	rows      []tgtg.Items
+	cursor    int
+	sortOrder int
+	stockOnly bool
+	query     string
+	editing   bool
+	updated   time.Time
+
+	// detail holds details of item selected with enter.
+	detail *tgtg.GetItemResponse
+
+	orders      []tgtg.Order
+	orderCursor int
+}
+
+func newModel(client clientFunc, request *tgtg.ListItemsRequest, interval time.Duration) model {
+	return model{client: client, request: request, interval: interval, status: "loading..."}
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(m.fetchItems(), m.fetchOrders(), m.tick())
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case tickMsg:
+		return m, tea.Batch(m.fetchItems(), m.tick())
+	case itemsMsg:
+		if msg.err != nil {
+			m.status = "listing items failed: " + errorMessage(msg.err)
+			return m, nil
+		}
+		m.items = msg.items
+		m.updated = time.Now()
+		m.status = ""
+		m.refreshRows()
+		return m, nil
+	case itemMsg:
+		if msg.err != nil {
+			m.status = "getting item failed: " + errorMessage(msg.err)
+			return m, nil
+		}
+		m.detail = msg.item
+		return m, nil
+	case ordersMsg:
+		if msg.err != nil {
+			m.status = "listing orders failed: " + errorMessage(msg.err)
+			return m, nil
+		}
+		m.orders = msg.orders
+		m.orderCursor = clamp(m.orderCursor, len(m.orders))
+		return m, nil
+	case favoriteMsg:
+		if msg.err != nil {
+			m.status = "setting favorite failed: " + errorMessage(msg.err)
+			return m, nil
+		}
+		m.setFavorite(msg.itemID, msg.favorite)
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateQuery(msg)
+		}
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+// updateQuery handles keys typed into store name filter.
+func (m model) updateQuery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.editing = false
+	case tea.KeyEsc:
+		m.editing = false
+		m.query = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	}
+	m.refreshRows()
+	return m, nil
+}
+
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "tab":
+		m.tab = (m.tab + 1) % 2
+	case "1":
+		m.tab = itemsTab
+	case "2":
+		m.tab = ordersTab
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "r":
+		m.status = "refreshing..."
+		return m, tea.Batch(m.fetchItems(), m.fetchOrders())
+	case "esc":
+		m.detail = nil
+	}
+
+	if m.tab != itemsTab {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "s":
+		m.sortOrder = (m.sortOrder + 1) % len(sortOrders)
+		m.refreshRows()
+	case "a":
+		m.stockOnly = !m.stockOnly
+		m.refreshRows()
+	case "/":
+		m.editing = true
+	case "enter":
+		if selected, ok := m.selected(); ok {
+			return m, m.fetchItem(selected.Item.ItemID)
+		}
+	case "f":
+		if selected, ok := m.selected(); ok {
+			return m, m.toggleFavorite(selected.Item.ItemID, !selected.Favorite)
+		}
+	}
+	return m, nil
+}
+
+// move moves cursor of current tab by delta rows.
+func (m *model) move(delta int) {
+	if m.tab == ordersTab {
+		m.orderCursor = clamp(m.orderCursor+delta, len(m.orders))
+		return
+	}
+	m.cursor = clamp(m.cursor+delta, len(m.rows))
+}
+
+// refreshRows applies filters and sort order to items, keeping cursor on selected item if possible.
+func (m *model) refreshRows() {
+	selected, hadSelection := m.selected()
+
+	var predicates []filter.Predicate
+	if m.stockOnly {
+		predicates = append(predicates, filter.Available())
+	}
+	if m.query != "" {
+		predicates = append(predicates, filter.StoreName(regexp.MustCompile("(?i)"+regexp.QuoteMeta(m.query))))
+	}
+	m.rows = filter.Filter(m.items, predicates...)
+	filter.Sort(m.rows, sortOrders[m.sortOrder].keys...)
+
+	m.cursor = clamp(m.cursor, len(m.rows))
+	if hadSelection {
+		for i, row := range m.rows {
+			if row.Item.ItemID == selected.Item.ItemID {
+				m.cursor = i
+			}
+		}
+	}
+}
+
+// selected returns item under cursor.
+func (m model) selected() (tgtg.Items, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return tgtg.Items{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// setFavorite updates favorite flag of item, without waiting for next refresh.
+func (m *model) setFavorite(itemID string, favorite bool) {
+	for i := range m.items {
+		if m.items[i].Item.ItemID == itemID {
+			m.items[i].Favorite = favorite
+		}
+	}
+	if m.detail != nil && m.detail.Item.ItemID == itemID {
+		m.detail.Favorite = favorite
+	}
+	m.refreshRows()
+}
+
+func (m model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m model) fetchItems() tea.Cmd {
+	client, request := m.client(), m.request
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		response, _, err := client.Items.List(ctx, request)
+		if err != nil {
+			return itemsMsg{err: err}
+		}
+		return itemsMsg{items: response.Items}
+	}
+}
+
+func (m model) fetchItem(itemID string) tea.Cmd {
+	client := m.client()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		response, _, err := client.Items.Get(ctx, &tgtg.GetItemRequest{}, itemID)
+		return itemMsg{item: response, err: err}
+	}
+}
+
+func (m model) fetchOrders() tea.Cmd {
+	client := m.client()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		active, _, err := client.Orders.Active(ctx, nil)
+		if err != nil {
+			return ordersMsg{err: err}
+		}
+		return ordersMsg{orders: active.Orders}
+	}
+}
+
+func (m model) toggleFavorite(itemID string, favorite bool) tea.Cmd {
+	client := m.client()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		_, err := client.Items.Favorite(ctx, &tgtg.FavoriteItemRequest{IsFavorite: favorite}, itemID)
+		return favoriteMsg{itemID: itemID, favorite: favorite, err: err}
+	}
+}
+
+// errorMessage returns single line message of err. Errors returned by Too Good To Go API are described
+// by their messages, or codes, instead of multi-line dump of the request.
+func errorMessage(err error) string {
+	var errorResponse *tgtg.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response == nil {
+		return err.Error()
+	}
+
+	var messages []string
+	for _, e := range errorResponse.Errors {
+		if e.Message != "" {
+			messages = append(messages, e.Message)
+		} else if e.Code != "" {
+			messages = append(messages, e.Code)
+		}
+	}
+	if len(messages) == 0 {
+		return errorResponse.Response.Status
+	}
+	return strings.Join(messages, ", ")
+}
+
+// clamp limits index to [0, length).
+func clamp(index, length int) int {
+	if index >= length {
+		index = length - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	return index
+}
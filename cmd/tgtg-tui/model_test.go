@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func setup(t *testing.T) (model, *http.ServeMux) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := tgtg.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.SetAuthContext("access", "refresh", "1")
+
+	request := &tgtg.ListItemsRequest{Origin: &tgtg.Origin{Latitude: 1, Longitude: 1}, Radius: 1}
+	m := newModel(func() *tgtg.Client { return client }, request, time.Minute)
+	return m, mux
+}
+
+func entry(id, store string, price int, distance float64, available int) tgtg.Items {
+	return tgtg.Items{
+		Item:           tgtg.Item{ItemID: id, Name: "bag-" + id, PriceIncludingTaxes: tgtg.Price{Code: "EUR", MinorUnits: price, Decimals: 2}},
+		Store:          tgtg.Store{StoreName: store},
+		Distance:       distance,
+		ItemsAvailable: available,
+	}
+}
+
+func update(m model, msgs ...tea.Msg) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		var updated tea.Model
+		updated, cmd = m.Update(msg)
+		m = updated.(model)
+	}
+	return m, cmd
+}
+
+func key(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func rowIDs(m model) []string {
+	var ids []string
+	for _, row := range m.rows {
+		ids = append(ids, row.Item.ItemID)
+	}
+	return ids
+}
+
+func TestModel_SortAndFilter(t *testing.T) {
+	m, _ := setup(t)
+	m, _ = update(m, itemsMsg{items: []tgtg.Items{
+		entry("1", "Bakery", 300, 2, 1),
+		entry("2", "Cafe", 100, 3, 0),
+		entry("3", "Baker's", 200, 1, 2),
+	}})
+
+	testCases := []struct {
+		title    string
+		keys     []string
+		expected []string
+	}{
+		{title: "by distance", expected: []string{"3", "1", "2"}},
+		{title: "by price", keys: []string{"s"}, expected: []string{"2", "3", "1"}},
+		{title: "available only", keys: []string{"a"}, expected: []string{"3", "1"}},
+		{title: "store name", keys: []string{"/", "b", "A", "k", "e", "r", "'", "enter"}, expected: []string{"3"}},
+		{title: "store name cleared", keys: []string{"/", "c", "esc"}, expected: []string{"3", "1", "2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			actual := m
+			for _, k := range tc.keys {
+				actual, _ = update(actual, key(k))
+			}
+			if !reflect.DeepEqual(rowIDs(actual), tc.expected) {
+				t.Errorf("Rows: %+v, expected: %+v", rowIDs(actual), tc.expected)
+			}
+		})
+	}
+}
+
+func TestModel_CursorFollowsSelection(t *testing.T) {
+	m, _ := setup(t)
+	items := []tgtg.Items{entry("1", "A", 300, 1, 1), entry("2", "B", 100, 2, 1)}
+	m, _ = update(m, itemsMsg{items: items}, key("down"))
+
+	if selected, _ := m.selected(); selected.Item.ItemID != "2" {
+		t.Fatalf("Selected: %+v, expected item 2", selected.Item.ItemID)
+	}
+
+	// Sorting by price moves item 2 to the top, cursor follows it.
+	m, _ = update(m, key("s"))
+	if selected, _ := m.selected(); selected.Item.ItemID != "2" || m.cursor != 0 {
+		t.Errorf("Selected: %+v at %d, expected item 2 at 0", selected.Item.ItemID, m.cursor)
+	}
+}
+
+func TestModel_DetailAndFavorite(t *testing.T) {
+	m, mux := setup(t)
+	mux.HandleFunc("/item/v7/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"item": {"item_id": "1", "description": "Fresh bread"}, "display_name": "Bakery - bag"}`)
+	})
+	var favorite tgtg.FavoriteItemRequest
+	mux.HandleFunc("/item/v7/1/setFavorite", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&favorite); err != nil {
+			t.Errorf("Decode json: %+v", err)
+		}
+	})
+
+	m, _ = update(m, itemsMsg{items: []tgtg.Items{entry("1", "Bakery", 300, 1, 1)}})
+
+	m, cmd := update(m, key("enter"))
+	if cmd == nil {
+		t.Fatal("Enter returned no command.")
+	}
+	m, _ = update(m, cmd())
+	if m.detail == nil || m.detail.DisplayName != "Bakery - bag" {
+		t.Fatalf("Detail: %+v, expected item 1", m.detail)
+	}
+	if view := m.View(); !strings.Contains(view, "Fresh bread") {
+		t.Errorf("View: %s, expected to contain item description", view)
+	}
+
+	m, cmd = update(m, key("f"))
+	if cmd == nil {
+		t.Fatal("f returned no command.")
+	}
+	m, _ = update(m, cmd())
+	if !favorite.IsFavorite || !m.items[0].Favorite || !m.detail.Favorite {
+		t.Errorf("Favorite request: %+v, item: %+v, expected item set as favorite", favorite, m.items[0])
+	}
+
+	m, _ = update(m, key("esc"))
+	if m.detail != nil {
+		t.Errorf("Detail: %+v, expected it to be closed", m.detail)
+	}
+}
+
+func TestModel_Orders(t *testing.T) {
+	m, mux := setup(t)
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"orders": [{"order_id": "1", "store_name": "Bakery", "state": "ACTIVE", "quantity": 2}]}`)
+	})
+
+	m, _ = update(m, m.fetchOrders()(), key("tab"))
+	if m.tab != ordersTab {
+		t.Fatalf("Tab: %d, expected orders tab", m.tab)
+	}
+	if view := m.View(); !strings.Contains(view, "Bakery") || !strings.Contains(view, "ACTIVE") {
+		t.Errorf("View: %s, expected to contain active order", view)
+	}
+}
+
+func TestModel_Error(t *testing.T) {
+	testCases := []struct {
+		title    string
+		body     string
+		expected string
+	}{
+		{title: "no body", expected: "listing items failed: 500 Internal Server Error"},
+		{title: "error message", body: `{"errors": [{"code": "FAILED", "message": "Something went wrong"}]}`, expected: "listing items failed: Something went wrong"},
+		{title: "error code", body: `{"errors": [{"code": "FAILED"}, {"code": "RETRY"}]}`, expected: "listing items failed: FAILED, RETRY"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			m, mux := setup(t)
+			mux.HandleFunc("/item/v7/", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, tc.body)
+			})
+
+			m, _ = update(m, itemsMsg{items: []tgtg.Items{entry("1", "Bakery", 300, 1, 1)}})
+			m, _ = update(m, m.fetchItems()())
+
+			if m.status != tc.expected {
+				t.Errorf("Status: %q, expected: %q", m.status, tc.expected)
+			}
+			if len(m.rows) != 1 {
+				t.Errorf("Rows: %+v, expected previous items to be kept", rowIDs(m))
+			}
+		})
+	}
+}
+
+func TestVisibleRows(t *testing.T) {
+	testCases := []struct {
+		title                  string
+		cursor, length, height int
+		from, to               int
+	}{
+		{title: "unknown height", cursor: 5, length: 10, height: 0, from: 0, to: 10},
+		{title: "fits", cursor: 1, length: 3, height: 5, from: 0, to: 3},
+		{title: "top", cursor: 2, length: 10, height: 5, from: 0, to: 5},
+		{title: "scrolled", cursor: 7, length: 10, height: 5, from: 3, to: 8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			from, to := visibleRows(tc.cursor, tc.length, tc.height)
+			if from != tc.from || to != tc.to {
+				t.Errorf("visibleRows returned: %d, %d, expected: %d, %d", from, to, tc.from, tc.to)
+			}
+		})
+	}
+}
+
+func TestFormatRow(t *testing.T) {
+	columns := []column{{width: 5}, {width: 4, right: true}}
+	if actual, expected := formatRow(columns, []string{"Bakery", "1"}), "Bake…    1"; actual != expected {
+		t.Errorf("formatRow returned: %q, expected: %q", actual, expected)
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/logrusorgru/aurora"
+)
+
+// chromeLines is number of lines rendered around tables: tabs, status, header and help.
+const chromeLines = 6
+
+// detailLines is number of lines rendered by item detail pane.
+const detailLines = 8
+
+func (m model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.viewTabs())
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(aurora.Yellow(m.status).String())
+	}
+	b.WriteString("\n")
+
+	switch m.tab {
+	case itemsTab:
+		b.WriteString(m.viewItems())
+		if m.detail != nil {
+			b.WriteString(m.viewDetail())
+		}
+	case ordersTab:
+		b.WriteString(m.viewOrders())
+	}
+
+	b.WriteString("\n")
+	b.WriteString(aurora.Faint(m.viewHelp()).String())
+	return b.String()
+}
+
+func (m model) viewTabs() string {
+	tabs := []string{"1 Items", "2 Orders"}
+	for i, title := range tabs {
+		if tab(i) == m.tab {
+			tabs[i] = aurora.Reverse(" " + title + " ").String()
+		} else {
+			tabs[i] = " " + title + " "
+		}
+	}
+
+	line := strings.Join(tabs, " ")
+	if m.tab == itemsTab {
+		line += fmt.Sprintf("  sort: %s", sortOrders[m.sortOrder].name)
+		if m.stockOnly {
+			line += "  available only"
+		}
+		if m.query != "" || m.editing {
+			line += "  store: " + m.query
+			if m.editing {
+				line += "_"
+			}
+		}
+		if !m.updated.IsZero() {
+			line += "  updated " + m.updated.Format("15:04:05")
+		}
+	}
+	return line
+}
+
+var itemColumns = []column{
+	{title: "", width: 1},
+	{title: "STORE", width: 24},
+	{title: "BAG", width: 22},
+	{title: "LEFT", width: 4, right: true},
+	{title: "PRICE", width: 8, right: true},
+	{title: "OFF", width: 4, right: true},
+	{title: "RATE", width: 4, right: true},
+	{title: "KM", width: 5, right: true},
+	{title: "PICKUP", width: 11},
+}
+
+func (m model) viewItems() string {
+	var b strings.Builder
+	b.WriteString(aurora.Bold(formatRow(itemColumns, columnTitles(itemColumns))).String())
+	b.WriteString("\n")
+
+	if len(m.rows) == 0 {
+		b.WriteString("no items\n")
+		return b.String()
+	}
+
+	from, to := visibleRows(m.cursor, len(m.rows), m.tableHeight())
+	for i := from; i < to; i++ {
+		line := formatRow(itemColumns, itemCells(m.rows[i]))
+		if i == m.cursor {
+			line = aurora.Reverse(line).String()
+		} else if m.rows[i].ItemsAvailable == 0 {
+			line = aurora.Faint(line).String()
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func itemCells(items tgtg.Items) []string {
+	favorite := ""
+	if items.Favorite {
+		favorite = "*"
+	}
+
+	discount := ""
+	if d, err := items.Item.Discount(); err == nil && d > 0 {
+		discount = fmt.Sprintf("%.0f%%", d)
+	}
+
+	return []string{
+		favorite,
+		items.Store.StoreName,
+		items.Item.Name,
+		fmt.Sprint(items.ItemsAvailable),
+		items.Item.PriceIncludingTaxes.String(),
+		discount,
+		fmt.Sprintf("%.1f", items.Item.AverageOverallRating.AverageOverallRating),
+		fmt.Sprintf("%.1f", items.Distance),
+		pickupWindow(items.PickupInterval, items.Store.StoreTimeZone),
+	}
+}
+
+func (m model) viewDetail() string {
+	d := m.detail
+	var b strings.Builder
+
+	b.WriteString("\n")
+	title := d.DisplayName
+	if d.Favorite {
+		title += " *"
+	}
+	b.WriteString(aurora.Bold(title).String())
+	b.WriteString("\n")
+
+	price := d.Item.PriceIncludingTaxes.String() + " " + d.Item.PriceIncludingTaxes.Code
+	if d.Item.ValueIncludingTaxes.MinorUnits > 0 {
+		price += " (value " + d.Item.ValueIncludingTaxes.String() + ")"
+	}
+	fmt.Fprintf(&b, "%d left, %s\n", d.ItemsAvailable, price)
+	fmt.Fprintf(&b, "pickup %s at %s\n", pickupWindow(d.PickupInterval, d.Store.StoreTimeZone), d.PickupLocation.Address.AddressLine)
+	fmt.Fprintf(&b, "rating %.1f (%d ratings)\n", d.Item.AverageOverallRating.AverageOverallRating, d.Item.AverageOverallRating.RatingCount)
+	b.WriteString(truncate(d.Item.Description, m.lineWidth()))
+	b.WriteString("\n")
+	if d.SharingURL != "" {
+		b.WriteString(d.SharingURL)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var orderColumns = []column{
+	{title: "STORE", width: 24},
+	{title: "BAG", width: 22},
+	{title: "QTY", width: 3, right: true},
+	{title: "PRICE", width: 8, right: true},
+	{title: "STATE", width: 10},
+	{title: "PICKUP", width: 17},
+	{title: "CANCEL UNTIL", width: 12},
+}
+
+func (m model) viewOrders() string {
+	var b strings.Builder
+	b.WriteString(aurora.Bold(formatRow(orderColumns, columnTitles(orderColumns))).String())
+	b.WriteString("\n")
+
+	if len(m.orders) == 0 {
+		b.WriteString("no active orders\n")
+		return b.String()
+	}
+
+	from, to := visibleRows(m.orderCursor, len(m.orders), m.tableHeight())
+	for i := from; i < to; i++ {
+		order := m.orders[i]
+
+		pickup := ""
+		if window, err := order.PickupWindow("", nil); err == nil && !window.IsZero() {
+			pickup = window.Start.Local().Format("Jan 02 15:04") + "-" + window.End.Local().Format("15:04")
+		}
+		cancelUntil := ""
+		if !order.CancelUntil.IsZero() {
+			cancelUntil = order.CancelUntil.Local().Format("Jan 02 15:04")
+		}
+
+		line := formatRow(orderColumns, []string{
+			order.StoreName,
+			order.ItemName,
+			fmt.Sprint(order.Quantity),
+			order.PriceIncludingTaxes.String(),
+			order.State,
+			pickup,
+			cancelUntil,
+		})
+		if i == m.orderCursor {
+			line = aurora.Reverse(line).String()
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (m model) viewHelp() string {
+	if m.editing {
+		return "type store name  enter apply  esc clear"
+	}
+	help := "tab switch  ↑/↓ move  r refresh  q quit"
+	if m.tab == itemsTab {
+		help = "tab switch  ↑/↓ move  enter details  f favorite  s sort  a available  / store  r refresh  q quit"
+	}
+	return help
+}
+
+// tableHeight returns number of table rows fitting the terminal, or 0 if its size is unknown.
+func (m model) tableHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	height := m.height - chromeLines
+	if m.tab == itemsTab && m.detail != nil {
+		height -= detailLines
+	}
+	if height < 3 {
+		height = 3
+	}
+	return height
+}
+
+func (m model) lineWidth() int {
+	if m.width == 0 {
+		return 80
+	}
+	return m.width
+}
+
+// visibleRows returns range of rows to render, scrolled so that cursor is visible. Zero height shows all rows.
+func visibleRows(cursor, length, height int) (int, int) {
+	if height <= 0 || length <= height {
+		return 0, length
+	}
+	from := 0
+	if cursor >= height {
+		from = cursor - height + 1
+	}
+	return from, from + height
+}
+
+// pickupWindow formats pickup interval in store's time zone.
+func pickupWindow(interval tgtg.PickupInterval, timeZone string) string {
+	window, err := tgtg.NewPickupWindow(interval, timeZone, nil)
+	if err != nil || window.IsZero() {
+		return ""
+	}
+	return window.String()
+}
+
+// column is a fixed width column of a table.
+type column struct {
+	title string
+	width int
+	right bool
+}
+
+func columnTitles(columns []column) []string {
+	titles := make([]string, len(columns))
+	for i, c := range columns {
+		titles[i] = c.title
+	}
+	return titles
+}
+
+// formatRow pads and truncates cells to column widths.
+func formatRow(columns []column, cells []string) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		cell := truncate(cells[i], c.width)
+		padding := strings.Repeat(" ", c.width-len([]rune(cell)))
+		if c.right {
+			parts[i] = padding + cell
+		} else {
+			parts[i] = cell + padding
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// truncate shortens text to width runes, marking truncation with ellipsis.
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,53 @@
+package tgtg
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Codec encodes request bodies and decodes response bodies exchanged with Too Good To Go API.
+// It allows swapping encoding/json for a faster, compatible JSON library.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// jsonCodec is the default Codec backed by encoding/json.
+type jsonCodec struct{}
+
+var _ Codec = jsonCodec{}
+
+// Marshal implements Codec interface's method.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements Codec interface's method.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// SetCodec is a ClientOption for setting Codec used for request and response bodies.
+func SetCodec(codec Codec) ClientOption {
+	return func(c *Client) error {
+		if codec == nil {
+			return NewArgumentError("codec", "must not be nil")
+		}
+		c.codec = codec
+		return nil
+	}
+}
+
+// SetKeepRawResponse is a ClientOption for keeping raw response body available for reading
+// in *http.Response returned by Do and services, after it has been decoded.
+func SetKeepRawResponse(keep bool) ClientOption {
+	return func(c *Client) error {
+		c.keepRawResponse = keep
+		return nil
+	}
+}
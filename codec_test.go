@@ -0,0 +1,110 @@
+package tgtg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type countingCodec struct {
+	marshaled, unmarshaled int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshaled++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshaled++
+	return json.Unmarshal(data, v)
+}
+
+func TestSetCodec(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	codec := &countingCodec{}
+	if err := SetCodec(codec)(client); err != nil {
+		t.Fatalf("SetCodec returned error: %+v", err)
+	}
+
+	mux.HandleFunc("/auth/v3/authByEmail", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"polling_id": "polling_id"}`)
+	})
+
+	actual, _, err := client.Auth.Login(context.Background(), &LoginRequest{Email: "some@email.com"})
+	if err != nil {
+		t.Fatalf("Auth.Login returned error: %+v", err)
+	}
+
+	if actual.PollingID != "polling_id" {
+		t.Errorf("Auth.Login returned: %+v, expected PollingID: polling_id", actual)
+	}
+
+	if codec.marshaled != 1 || codec.unmarshaled != 1 {
+		t.Errorf("Codec used: %+v, expected one Marshal and one Unmarshal", codec)
+	}
+
+	if _, err := New(nil, SetCodec(nil)); err == nil {
+		t.Error("New returned no error for nil codec.")
+	}
+}
+
+func TestSetKeepRawResponse(t *testing.T) {
+	body := `{"polling_id": "polling_id", "state": "state"}`
+
+	testCases := []struct {
+		title    string
+		keep     bool
+		expected string
+	}{
+		{title: "kept", keep: true, expected: body},
+		{title: "not kept", keep: false, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			client, mux, teardown := setup()
+			defer teardown()
+
+			if err := SetKeepRawResponse(tc.keep)(client); err != nil {
+				t.Fatalf("SetKeepRawResponse returned error: %+v", err)
+			}
+
+			mux.HandleFunc("/auth/v3/authByEmail", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, body)
+			})
+
+			_, response, err := client.Auth.Login(context.Background(), &LoginRequest{})
+			if err != nil {
+				t.Fatalf("Auth.Login returned error: %+v", err)
+			}
+
+			raw, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatalf("Read body: %+v", err)
+			}
+			if string(raw) != tc.expected {
+				t.Errorf("Response body: %+v, expected: %+v", string(raw), tc.expected)
+			}
+		})
+	}
+}
+
+func TestDo_EmptyBody(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/auth/v3/authByRequestPollingId", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	_, _, err := client.Auth.Poll(context.Background(), &PollRequest{})
+	if err == nil || err.Error() != "EOF" {
+		t.Errorf("Auth.Poll returned: %+v, expected: EOF", err)
+	}
+}
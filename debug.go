@@ -0,0 +1,92 @@
+package tgtg
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders lists HTTP headers whose values are never dumped.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// sensitiveFields matches JSON string fields whose values are never dumped.
+var sensitiveFields = regexp.MustCompile(`("(?:access_token|refresh_token|email|polling_id|request_polling_id)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// debugDumper writes requests and responses with secrets redacted.
+type debugDumper struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// SetDebugDump is a ClientOption enabling dumping of every request and response, including bodies, to w.
+// Tokens, emails and authorization headers are redacted.
+func SetDebugDump(w io.Writer) ClientOption {
+	return func(c *Client) error {
+		if w == nil {
+			return NewArgumentError("w", "must not be nil")
+		}
+		c.debug = &debugDumper{w: w}
+		return nil
+	}
+}
+
+// dumpRequest dumps request line, headers and body.
+func (d *debugDumper) dumpRequest(req *http.Request) {
+	var body []byte
+	if req.GetBody != nil {
+		if reader, err := req.GetBody(); err == nil {
+			body, _ = ioutil.ReadAll(reader)
+		}
+	}
+
+	d.dump(fmt.Sprintf("--> %s %s", req.Method, req.URL), req.Header, body)
+}
+
+// dumpResponse dumps status line, headers and body of response to req. Request of response is not used,
+// as transports are not required to set it.
+func (d *debugDumper) dumpResponse(req *http.Request, response *http.Response, body []byte) {
+	d.dump(fmt.Sprintf("<-- %d %s %s", response.StatusCode, req.Method, req.URL), response.Header, body)
+}
+
+func (d *debugDumper) dump(line string, header http.Header, body []byte) {
+	var b strings.Builder
+	b.WriteString(line + "\n")
+
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.Join(header[key], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			value = redacted
+		}
+		b.WriteString(key + ": " + value + "\n")
+	}
+
+	if len(body) > 0 {
+		b.WriteString("\n" + redactBody(string(body)) + "\n")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Fprintln(d.w, b.String())
+}
+
+// redactBody replaces values of sensitive JSON fields in body.
+func redactBody(body string) string {
+	return sensitiveFields.ReplaceAllString(strings.TrimSpace(body), `$1"`+redacted+`"`)
+}
@@ -0,0 +1,79 @@
+package tgtg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetDebugDump(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	out := &bytes.Buffer{}
+	if err := SetDebugDump(out)(client); err != nil {
+		t.Fatalf("SetDebugDump returned error: %+v", err)
+	}
+	client.SetAuthContext("secret-access", "secret-refresh", "1")
+
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "secret-new-access", "refresh_token": "secret-new-refresh", "access_token_ttl_seconds": 1}`)
+	})
+
+	_, _, err := client.Auth.Refresh(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Auth.Refresh returned error: %+v", err)
+	}
+
+	dump := out.String()
+	if strings.Contains(dump, "secret") {
+		t.Errorf("Dump: %+v, contains secrets", dump)
+	}
+
+	for _, expected := range []string{"--> POST", "<-- 200 POST", `"refresh_token":"[REDACTED]"`, `"access_token_ttl_seconds": 1`} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("Dump: %+v, did not contain %+v", dump, expected)
+		}
+	}
+}
+
+// roundTripFunc is http.RoundTripper stub.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestSetDebugDump_ResponseWithoutRequest(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"orders": []}`))}, nil
+	})
+
+	out := &bytes.Buffer{}
+	client, err := New(&http.Client{Transport: transport}, SetDebugDump(out))
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	client.SetAuthContext("access", "refresh", "1")
+
+	if _, _, err := client.Orders.Active(context.Background(), nil); err != nil {
+		t.Fatalf("Orders.Active returned error: %+v", err)
+	}
+
+	if dump, expected := out.String(), "<-- 200 POST "+client.BaseURL.String(); !strings.Contains(dump, expected) {
+		t.Errorf("Dump: %+v, did not contain %+v", dump, expected)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	actual := redactBody(`{"email": "some@email.com", "access_token": "a\"b", "user_id": "1"}`)
+	expected := `{"email": "[REDACTED]", "access_token": "[REDACTED]", "user_id": "1"}`
+	if actual != expected {
+		t.Errorf("redactBody returned: %+v, expected: %+v", actual, expected)
+	}
+}
@@ -3,6 +3,8 @@ package tgtg
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/logrusorgru/aurora"
 )
@@ -63,3 +65,70 @@ type Error struct {
 	// Message is optional and not always returned.
 	Message string `json:"message"`
 }
+
+// CurrencyMismatchError is an error that represents an attempt to combine
+// prices expressed in different currencies.
+type CurrencyMismatchError struct {
+	// first specifies currency code of the first price.
+	first string
+
+	// second specifies currency code of the second price.
+	second string
+}
+
+var _ error = &CurrencyMismatchError{}
+
+// Error implements error interface's method.
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("currency mismatch (%s and %s)", e.first, e.second)
+}
+
+// NewCurrencyMismatchError creates a CurrencyMismatchError.
+func NewCurrencyMismatchError(first, second string) *CurrencyMismatchError {
+	return &CurrencyMismatchError{
+		first:  first,
+		second: second,
+	}
+}
+
+// ItemsError is an error that represents failures of getting multiple items, identified by item ID.
+type ItemsError struct {
+	// Errors by item ID.
+	Errors map[string]error
+}
+
+var _ error = &ItemsError{}
+
+// Error implements error interface's method.
+func (e *ItemsError) Error() string {
+	return fmt.Sprintf("%d item(s) failed (%s)", len(e.Errors), joinErrors(e.Errors))
+}
+
+// AccountsError is an error that represents failures of an operation performed
+// for multiple accounts, identified by account email.
+type AccountsError struct {
+	// Errors by account email.
+	Errors map[string]error
+}
+
+var _ error = &AccountsError{}
+
+// Error implements error interface's method.
+func (e *AccountsError) Error() string {
+	return fmt.Sprintf("%d account(s) failed (%s)", len(e.Errors), joinErrors(e.Errors))
+}
+
+// joinErrors joins errors sorted by their keys into single message.
+func joinErrors(errs map[string]error) string {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %v", key, errs[key]))
+	}
+	return strings.Join(messages, "; ")
+}
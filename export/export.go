@@ -0,0 +1,111 @@
+// Package export writes Too Good To Go data in formats usable outside of this library:
+// CSV and JSON Lines for personal records, iCalendar for calendar reminders, and GeoJSON and KML for maps.
+//
+//	response, _, err := client.Orders.Inactive(ctx, request)
+//	...
+//	err = export.OrdersCSV(file, response.Orders)
+//
+//	items, _, err := client.Items.List(ctx, request)
+//	...
+//	err = export.ItemsGeoJSON(file, items.Items)
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// ordersCSVHeader lists columns written by OrdersCSV.
+var ordersCSVHeader = []string{
+	"order_id",
+	"state",
+	"time_of_purchase",
+	"pickup_start",
+	"pickup_end",
+	"cancel_until",
+	"store_id",
+	"store_name",
+	"store_branch",
+	"item_id",
+	"item_name",
+	"quantity",
+	"currency",
+	"price_including_taxes",
+	"price_excluding_taxes",
+	"total_applied_taxes",
+	"address",
+	"city",
+	"postal_code",
+	"country",
+	"latitude",
+	"longitude",
+}
+
+// OrdersCSV writes orders as CSV with a header row. Prices are written as decimal numbers,
+// e.g. 3.99, with currency in a separate column. Times are written in RFC 3339 format, empty if unknown.
+func OrdersCSV(w io.Writer, orders []tgtg.Order) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ordersCSVHeader); err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		address := order.PickupLocation.Address
+		location := order.PickupLocation.Location
+
+		record := []string{
+			order.OrderID,
+			order.State,
+			formatTime(order.TimeOfPurchase),
+			formatTime(order.PickupInterval.Start),
+			formatTime(order.PickupInterval.End),
+			formatTime(order.CancelUntil),
+			order.StoreID,
+			order.StoreName,
+			order.StoreBranch,
+			order.ItemID,
+			order.ItemName,
+			strconv.Itoa(order.Quantity),
+			order.PriceIncludingTaxes.Code,
+			order.PriceIncludingTaxes.String(),
+			order.PriceExcludingTaxes.String(),
+			order.TotalAppliedTaxes.String(),
+			address.AddressLine,
+			address.City,
+			address.PostalCode,
+			address.Country.IsoCode,
+			strconv.FormatFloat(location.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(location.Longitude, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// OrdersJSONL writes orders as JSON Lines, one JSON encoded order per line.
+func OrdersJSONL(w io.Writer, orders []tgtg.Order) error {
+	encoder := json.NewEncoder(w)
+	for _, order := range orders {
+		if err := encoder.Encode(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatTime formats timestamp in RFC 3339 format, or returns empty string if it is zero.
+func formatTime(t tgtg.Timestamp) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
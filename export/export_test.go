@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+var orders = []tgtg.Order{
+	{
+		OrderID:             "1",
+		State:               "ACTIVE",
+		CancelUntil:         tgtg.Timestamp{Time: time.Date(2021, 12, 1, 16, 0, 0, 0, time.UTC)},
+		PickupInterval:      tgtg.PickupInterval{Start: tgtg.Timestamp{Time: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)}, End: tgtg.Timestamp{Time: time.Date(2021, 12, 1, 18, 30, 0, 0, time.UTC)}},
+		Quantity:            2,
+		PriceIncludingTaxes: tgtg.Price{Code: "PLN", MinorUnits: 1998, Decimals: 2},
+		PriceExcludingTaxes: tgtg.Price{Code: "PLN", MinorUnits: 1850, Decimals: 2},
+		TotalAppliedTaxes:   tgtg.Price{Code: "PLN", MinorUnits: 148, Decimals: 2},
+		PickupLocation: tgtg.PickupLocation{
+			Address:  tgtg.Address{AddressLine: "Main Street 1, Warsaw", City: "Warsaw", PostalCode: "00-001", Country: tgtg.Country{IsoCode: "PL", Name: "Poland"}},
+			Location: tgtg.Location{Latitude: 52.23, Longitude: 21.01},
+		},
+		TimeOfPurchase: tgtg.Timestamp{Time: time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)},
+		StoreID:        "s1",
+		StoreName:      "Bakery",
+		StoreBranch:    "Center",
+		ItemID:         "i1",
+		ItemName:       "Magic Bag",
+	},
+	{
+		OrderID:   "2",
+		State:     "CANCELLED",
+		StoreName: "Cafe, \"Best\"",
+	},
+}
+
+func TestOrdersCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := OrdersCSV(&buf, orders); err != nil {
+		t.Fatalf("OrdersCSV returned error: %+v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Read CSV: %+v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("OrdersCSV wrote %d records, expected: 3", len(records))
+	}
+	if !reflect.DeepEqual(records[0], ordersCSVHeader) {
+		t.Errorf("OrdersCSV wrote header: %+v, expected: %+v", records[0], ordersCSVHeader)
+	}
+
+	expected := []string{
+		"1", "ACTIVE", "2021-12-01T12:00:00Z", "2021-12-01T18:00:00Z", "2021-12-01T18:30:00Z", "2021-12-01T16:00:00Z",
+		"s1", "Bakery", "Center", "i1", "Magic Bag", "2", "PLN", "19.98", "18.50", "1.48",
+		"Main Street 1, Warsaw", "Warsaw", "00-001", "PL", "52.23", "21.01",
+	}
+	if !reflect.DeepEqual(records[1], expected) {
+		t.Errorf("OrdersCSV wrote: %+v, expected: %+v", records[1], expected)
+	}
+
+	if actual := records[2][7]; actual != orders[1].StoreName {
+		t.Errorf("OrdersCSV wrote store name: %+v, expected: %+v", actual, orders[1].StoreName)
+	}
+	if actual := records[2][2]; actual != "" {
+		t.Errorf("OrdersCSV wrote time of purchase: %+v, expected empty", actual)
+	}
+}
+
+func TestOrdersJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := OrdersJSONL(&buf, orders); err != nil {
+		t.Fatalf("OrdersJSONL returned error: %+v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(orders) {
+		t.Fatalf("OrdersJSONL wrote %d lines, expected: %d", len(lines), len(orders))
+	}
+
+	for i, line := range lines {
+		var actual tgtg.Order
+		if err := json.Unmarshal([]byte(line), &actual); err != nil {
+			t.Fatalf("Decode json: %+v", err)
+		}
+		if actual.OrderID != orders[i].OrderID || !actual.PickupInterval.Start.Equal(orders[i].PickupInterval.Start.Time) {
+			t.Errorf("OrdersJSONL wrote: %+v, expected: %+v", actual, orders[i])
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// itemProperties are properties of items entry placed on a map.
+type itemProperties struct {
+	ItemID         string `json:"item_id"`
+	StoreID        string `json:"store_id"`
+	StoreName      string `json:"store_name"`
+	DisplayName    string `json:"display_name"`
+	Address        string `json:"address,omitempty"`
+	Price          string `json:"price"`
+	Currency       string `json:"currency"`
+	ItemsAvailable int    `json:"items_available"`
+	PickupStart    string `json:"pickup_start,omitempty"`
+	PickupEnd      string `json:"pickup_end,omitempty"`
+	PickupWindow   string `json:"pickup_window,omitempty"`
+}
+
+// itemsFeature represents items entry on a map.
+type itemsFeature struct {
+	location   tgtg.Location
+	properties itemProperties
+}
+
+// itemsFeatures returns map features of items entries. Entries with no pickup location nor store location are skipped.
+func itemsFeatures(items []tgtg.Items) []itemsFeature {
+	features := make([]itemsFeature, 0, len(items))
+	for _, entry := range items {
+		location := entry.PickupLocation
+		if location == (tgtg.Location{}) {
+			location = entry.Store.StoreLocation.Location
+		}
+		if location == (tgtg.Location{}) {
+			continue
+		}
+
+		properties := itemProperties{
+			ItemID:         entry.Item.ItemID,
+			StoreID:        entry.Store.StoreID,
+			StoreName:      entry.Store.StoreName,
+			DisplayName:    entry.DisplayName,
+			Address:        address(entry.Store.StoreLocation.Address),
+			Price:          entry.Item.PriceIncludingTaxes.String(),
+			Currency:       entry.Item.PriceIncludingTaxes.Code,
+			ItemsAvailable: entry.ItemsAvailable,
+			PickupStart:    formatTime(entry.PickupInterval.Start),
+			PickupEnd:      formatTime(entry.PickupInterval.End),
+		}
+		if window, err := entry.PickupWindow(nil); err == nil && !window.IsZero() {
+			properties.PickupWindow = window.String()
+		}
+
+		features = append(features, itemsFeature{location: location, properties: properties})
+	}
+	return features
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Geometry   geoJSONPoint   `json:"geometry"`
+	Properties itemProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// ItemsGeoJSON writes items entries as GeoJSON FeatureCollection of points at their pickup locations,
+// with store name, price, available count and pickup window as feature properties.
+// Entries without known location are skipped.
+func ItemsGeoJSON(w io.Writer, items []tgtg.Items) error {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+	for _, feature := range itemsFeatures(items) {
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			ID:   feature.properties.ItemID,
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{feature.location.Longitude, feature.location.Latitude},
+			},
+			Properties: feature.properties,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(collection)
+}
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Name       string         `xml:"Document>name"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	ID          string    `xml:"id,attr,omitempty"`
+	Name        string    `xml:"name"`
+	Description string    `xml:"description"`
+	Data        []kmlData `xml:"ExtendedData>Data"`
+	Coordinates string    `xml:"Point>coordinates"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+// ItemsKML writes items entries as KML document of placemarks at their pickup locations,
+// with store name, price, available count and pickup window as extended data.
+// Entries without known location are skipped.
+func ItemsKML(w io.Writer, items []tgtg.Items) error {
+	document := kmlDocument{Name: "Too Good To Go"}
+	for _, feature := range itemsFeatures(items) {
+		p := feature.properties
+		placemark := kmlPlacemark{
+			ID:          "item-" + p.ItemID,
+			Name:        p.DisplayName,
+			Description: p.StoreName + ": " + strconv.Itoa(p.ItemsAvailable) + " available, " + p.Price + " " + p.Currency,
+			Data: []kmlData{
+				{Name: "item_id", Value: p.ItemID},
+				{Name: "store_id", Value: p.StoreID},
+				{Name: "store_name", Value: p.StoreName},
+				{Name: "address", Value: p.Address},
+				{Name: "price", Value: p.Price},
+				{Name: "currency", Value: p.Currency},
+				{Name: "items_available", Value: strconv.Itoa(p.ItemsAvailable)},
+				{Name: "pickup_start", Value: p.PickupStart},
+				{Name: "pickup_end", Value: p.PickupEnd},
+				{Name: "pickup_window", Value: p.PickupWindow},
+			},
+			Coordinates: strconv.FormatFloat(feature.location.Longitude, 'f', -1, 64) + "," +
+				strconv.FormatFloat(feature.location.Latitude, 'f', -1, 64),
+		}
+		if p.PickupWindow != "" {
+			placemark.Description += ", pickup " + p.PickupWindow
+		}
+		document.Placemarks = append(document.Placemarks, placemark)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+var items = []tgtg.Items{
+	{
+		Item: tgtg.Item{ItemID: "1", PriceIncludingTaxes: tgtg.Price{Code: "PLN", MinorUnits: 999, Decimals: 2}},
+		Store: tgtg.Store{
+			StoreID:       "s1",
+			StoreName:     "Bakery",
+			StoreTimeZone: "Europe/Warsaw",
+			StoreLocation: tgtg.StoreLocation{Address: tgtg.Address{AddressLine: "Main Street 1"}},
+		},
+		DisplayName:    "Bakery - Magic Bag",
+		PickupInterval: tgtg.PickupInterval{Start: tgtg.Timestamp{Time: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)}, End: tgtg.Timestamp{Time: time.Date(2021, 12, 1, 18, 30, 0, 0, time.UTC)}},
+		PickupLocation: tgtg.Location{Latitude: 52.23, Longitude: 21.01},
+		ItemsAvailable: 3,
+	},
+	{
+		Item:  tgtg.Item{ItemID: "2"},
+		Store: tgtg.Store{StoreID: "s2", StoreLocation: tgtg.StoreLocation{Location: tgtg.Location{Latitude: 50.06, Longitude: 19.94}}},
+	},
+	{
+		Item: tgtg.Item{ItemID: "3"},
+	},
+}
+
+func TestItemsGeoJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ItemsGeoJSON(&buf, items); err != nil {
+		t.Fatalf("ItemsGeoJSON returned error: %+v", err)
+	}
+
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("Decode json: %+v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Fatalf("ItemsGeoJSON wrote: %+v, expected FeatureCollection with 2 features", collection)
+	}
+
+	expected := geoJSONFeature{
+		Type:     "Feature",
+		ID:       "1",
+		Geometry: geoJSONPoint{Type: "Point", Coordinates: [2]float64{21.01, 52.23}},
+		Properties: itemProperties{
+			ItemID:         "1",
+			StoreID:        "s1",
+			StoreName:      "Bakery",
+			DisplayName:    "Bakery - Magic Bag",
+			Address:        "Main Street 1",
+			Price:          "9.99",
+			Currency:       "PLN",
+			ItemsAvailable: 3,
+			PickupStart:    "2021-12-01T18:00:00Z",
+			PickupEnd:      "2021-12-01T18:30:00Z",
+			PickupWindow:   "19:00-19:30",
+		},
+	}
+	if !reflect.DeepEqual(collection.Features[0], expected) {
+		t.Errorf("ItemsGeoJSON wrote: %+v, expected: %+v", collection.Features[0], expected)
+	}
+
+	if actual := collection.Features[1].Geometry.Coordinates; actual != [2]float64{19.94, 50.06} {
+		t.Errorf("ItemsGeoJSON wrote coordinates: %+v, expected store location", actual)
+	}
+}
+
+func TestItemsGeoJSON_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ItemsGeoJSON(&buf, nil); err != nil {
+		t.Fatalf("ItemsGeoJSON returned error: %+v", err)
+	}
+
+	if expected := `{"type":"FeatureCollection","features":[]}` + "\n"; buf.String() != expected {
+		t.Errorf("ItemsGeoJSON wrote: %+v, expected: %+v", buf.String(), expected)
+	}
+}
+
+func TestItemsKML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ItemsKML(&buf, items); err != nil {
+		t.Fatalf("ItemsKML returned error: %+v", err)
+	}
+
+	var document kmlDocument
+	if err := xml.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Decode xml: %+v", err)
+	}
+	if len(document.Placemarks) != 2 {
+		t.Fatalf("ItemsKML wrote %d placemarks, expected: 2", len(document.Placemarks))
+	}
+
+	placemark := document.Placemarks[0]
+	if placemark.ID != "item-1" || placemark.Name != "Bakery - Magic Bag" || placemark.Coordinates != "21.01,52.23" {
+		t.Errorf("ItemsKML wrote: %+v, expected placemark of item 1", placemark)
+	}
+	if expected := "Bakery: 3 available, 9.99 PLN, pickup 19:00-19:30"; placemark.Description != expected {
+		t.Errorf("ItemsKML wrote description: %+v, expected: %+v", placemark.Description, expected)
+	}
+}
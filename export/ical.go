@@ -0,0 +1,141 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+const icalTimeFormat = "20060102T150405Z"
+
+// icalEscaper escapes iCalendar TEXT values.
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// OrdersICS writes orders as iCalendar, with one event per order pickup interval at order pickup location.
+// If order can still be cancelled, event has a reminder set at cancel-until time.
+// Orders without pickup interval are skipped.
+func OrdersICS(w io.Writer, orders []tgtg.Order) error {
+	calendar := &icalWriter{w: w}
+	calendar.line("BEGIN:VCALENDAR")
+	calendar.line("VERSION:2.0")
+	calendar.line("PRODID:-//tgt-go//Too Good To Go orders//EN")
+	calendar.line("CALSCALE:GREGORIAN")
+
+	for _, order := range orders {
+		if order.PickupInterval.Start.IsZero() || order.PickupInterval.End.IsZero() {
+			continue
+		}
+
+		stamp := order.TimeOfPurchase.Time
+		if stamp.IsZero() {
+			stamp = order.PickupInterval.Start.Time
+		}
+
+		calendar.line("BEGIN:VEVENT")
+		calendar.line("UID:" + order.OrderID + "@toogoodtogo")
+		calendar.line("DTSTAMP:" + icalTime(stamp))
+		calendar.line("DTSTART:" + icalTime(order.PickupInterval.Start.Time))
+		calendar.line("DTEND:" + icalTime(order.PickupInterval.End.Time))
+		calendar.line("SUMMARY:" + icalText("Too Good To Go: "+storeName(order)))
+		if location := address(order.PickupLocation.Address); location != "" {
+			calendar.line("LOCATION:" + icalText(location))
+		}
+		if l := order.PickupLocation.Location; l.Latitude != 0 || l.Longitude != 0 {
+			calendar.line(fmt.Sprintf("GEO:%f;%f", l.Latitude, l.Longitude))
+		}
+		calendar.line("DESCRIPTION:" + icalText(description(order)))
+
+		if !order.CancelUntil.IsZero() && order.CancelUntil.Before(order.PickupInterval.Start.Time) {
+			calendar.line("BEGIN:VALARM")
+			calendar.line("ACTION:DISPLAY")
+			calendar.line("DESCRIPTION:" + icalText("Last chance to cancel order at "+storeName(order)))
+			calendar.line("TRIGGER;VALUE=DATE-TIME:" + icalTime(order.CancelUntil.Time))
+			calendar.line("END:VALARM")
+		}
+
+		calendar.line("END:VEVENT")
+	}
+
+	calendar.line("END:VCALENDAR")
+	return calendar.err
+}
+
+// icalWriter writes iCalendar content lines, folding them at 75 octets, and keeps first error.
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *icalWriter) line(line string) {
+	if c.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		// Do not split UTF-8 encoded runes.
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+
+	_, c.err = io.WriteString(c.w, b.String())
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format(icalTimeFormat)
+}
+
+func icalText(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+// storeName returns store name with branch, if set.
+func storeName(order tgtg.Order) string {
+	if order.StoreBranch == "" {
+		return order.StoreName
+	}
+	return order.StoreName + " - " + order.StoreBranch
+}
+
+// address returns single line postal address.
+func address(address tgtg.Address) string {
+	if address.AddressLine != "" {
+		return address.AddressLine
+	}
+
+	var parts []string
+	for _, part := range []string{address.PostalCode, address.City, address.Country.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func description(order tgtg.Order) string {
+	lines := []string{
+		fmt.Sprintf("%dx %s", order.Quantity, order.ItemName),
+		"Store: " + storeName(order),
+	}
+	if order.PriceIncludingTaxes.Code != "" {
+		lines = append(lines, "Price: "+order.PriceIncludingTaxes.String()+" "+order.PriceIncludingTaxes.Code)
+	}
+	if !order.CancelUntil.IsZero() {
+		lines = append(lines, "Cancel until: "+order.CancelUntil.UTC().Format(time.RFC3339))
+	}
+	lines = append(lines, "Order: "+order.OrderID)
+	return strings.Join(lines, "\n")
+}
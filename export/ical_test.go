@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func TestOrdersICS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := OrdersICS(&buf, orders); err != nil {
+		t.Fatalf("OrdersICS returned error: %+v", err)
+	}
+	actual := buf.String()
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//tgt-go//Too Good To Go orders//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		"UID:1@toogoodtogo",
+		"DTSTAMP:20211201T120000Z",
+		"DTSTART:20211201T180000Z",
+		"DTEND:20211201T183000Z",
+		"SUMMARY:Too Good To Go: Bakery - Center",
+		`LOCATION:Main Street 1\, Warsaw`,
+		"GEO:52.230000;21.010000",
+		`DESCRIPTION:2x Magic Bag\nStore: Bakery - Center\nPrice: 19.98 PLN\nCancel `,
+		` until: 2021-12-01T16:00:00Z\nOrder: 1`,
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Last chance to cancel order at Bakery - Center",
+		"TRIGGER;VALUE=DATE-TIME:20211201T160000Z",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if actual != expected {
+		t.Errorf("OrdersICS wrote: %q, expected: %q", actual, expected)
+	}
+}
+
+func TestIcalWriter_Folding(t *testing.T) {
+	var buf bytes.Buffer
+	calendar := &icalWriter{w: &buf}
+	calendar.line("SUMMARY:" + strings.Repeat("ż", 80))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line %q is %d octets long, expected at most 75", line, len(line))
+		}
+		if !strings.HasPrefix(line, "SUMMARY") && !strings.HasPrefix(line, " ") {
+			t.Errorf("Continuation line %q does not start with space", line)
+		}
+	}
+
+	if unfolded := strings.ReplaceAll(buf.String(), "\r\n ", ""); unfolded != "SUMMARY:"+strings.Repeat("ż", 80)+"\r\n" {
+		t.Errorf("Unfolded line: %q, expected original line", unfolded)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	actual := address(tgtg.Address{City: "Warsaw", PostalCode: "00-001", Country: tgtg.Country{Name: "Poland"}})
+	if expected := "00-001, Warsaw, Poland"; actual != expected {
+		t.Errorf("address returned: %+v, expected: %+v", actual, expected)
+	}
+}
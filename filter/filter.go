@@ -0,0 +1,147 @@
+// Package filter provides composable client-side filtering and sorting of Too Good To Go items,
+// complementing limited filters of Too Good To Go API.
+//
+//	cheap := filter.Filter(response.Items,
+//		filter.InSalesWindow(),
+//		filter.MinDiscount(60),
+//		filter.Or(filter.Diet(tgtg.DietCategoryVegan), filter.Diet(tgtg.DietCategoryVegetarian)),
+//	)
+//	filter.Sort(cheap, filter.ByDistance, filter.Desc(filter.ByRating))
+package filter
+
+import (
+	"regexp"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// Predicate reports whether items entry matches a condition.
+type Predicate func(items tgtg.Items) bool
+
+// Filter returns entries matching all predicates, preserving their order. Input slice is not modified.
+func Filter(items []tgtg.Items, predicates ...Predicate) []tgtg.Items {
+	match := And(predicates...)
+
+	filtered := make([]tgtg.Items, 0, len(items))
+	for _, entry := range items {
+		if match(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// And returns Predicate matching entries matching all predicates. With no predicates it matches everything.
+func And(predicates ...Predicate) Predicate {
+	return func(items tgtg.Items) bool {
+		for _, predicate := range predicates {
+			if !predicate(items) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns Predicate matching entries matching any of predicates. With no predicates it matches nothing.
+func Or(predicates ...Predicate) Predicate {
+	return func(items tgtg.Items) bool {
+		for _, predicate := range predicates {
+			if predicate(items) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns Predicate negating predicate.
+func Not(predicate Predicate) Predicate {
+	return func(items tgtg.Items) bool {
+		return !predicate(items)
+	}
+}
+
+// PriceBetween matches entries with Item.PriceIncludingTaxes within [min, max].
+// Entries priced in different currency than min and max never match.
+func PriceBetween(min, max tgtg.Price) Predicate {
+	return func(items tgtg.Items) bool {
+		price := items.Item.PriceIncludingTaxes
+		lower, err := price.Cmp(min)
+		if err != nil {
+			return false
+		}
+		upper, err := price.Cmp(max)
+		if err != nil {
+			return false
+		}
+		return lower >= 0 && upper <= 0
+	}
+}
+
+// MinDiscount matches entries discounted by at least percentage, see tgtg.Item.Discount.
+func MinDiscount(percentage float64) Predicate {
+	return func(items tgtg.Items) bool {
+		discount, err := items.Item.Discount()
+		return err == nil && discount >= percentage
+	}
+}
+
+// MinRating matches entries with average overall rating of at least rating.
+func MinRating(rating float64) Predicate {
+	return func(items tgtg.Items) bool {
+		return items.Item.AverageOverallRating.AverageOverallRating >= rating
+	}
+}
+
+// MaxDistance matches entries not further than distance, expressed in units of Items.Distance.
+func MaxDistance(distance float64) Predicate {
+	return func(items tgtg.Items) bool {
+		return items.Distance <= distance
+	}
+}
+
+// PickupOverlaps matches entries with pickup interval overlapping [start, end).
+func PickupOverlaps(start, end time.Time) Predicate {
+	return func(items tgtg.Items) bool {
+		interval := items.PickupInterval
+		if interval.Start.IsZero() || interval.End.IsZero() {
+			return false
+		}
+		return interval.Start.Before(end) && interval.End.After(start)
+	}
+}
+
+// StoreName matches entries with store name matching re.
+func StoreName(re *regexp.Regexp) Predicate {
+	return func(items tgtg.Items) bool {
+		return re.MatchString(items.Store.StoreName)
+	}
+}
+
+// Diet matches entries of given diet category.
+func Diet(category tgtg.DietCategory) Predicate {
+	return func(items tgtg.Items) bool {
+		for _, diet := range items.Item.DietCategories {
+			if diet == string(category) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// InSalesWindow matches entries which can be currently bought.
+func InSalesWindow() Predicate {
+	return func(items tgtg.Items) bool {
+		return items.InSalesWindow
+	}
+}
+
+// Available matches entries with at least one item available.
+func Available() Predicate {
+	return func(items tgtg.Items) bool {
+		return items.ItemsAvailable > 0
+	}
+}
@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func eur(minorUnits int) tgtg.Price {
+	return tgtg.Price{Code: "EUR", Decimals: 2, MinorUnits: minorUnits}
+}
+
+func entry(id, store string, price, value int, rating, distance float64, available int, start time.Time, diets ...string) tgtg.Items {
+	return tgtg.Items{
+		Item: tgtg.Item{
+			ItemID:               id,
+			PriceIncludingTaxes:  eur(price),
+			ValueIncludingTaxes:  eur(value),
+			AverageOverallRating: tgtg.AverageOverallRating{AverageOverallRating: rating},
+			DietCategories:       diets,
+		},
+		Store:          tgtg.Store{StoreName: store},
+		Distance:       distance,
+		ItemsAvailable: available,
+		InSalesWindow:  available > 0,
+		PickupInterval: tgtg.PickupInterval{
+			Start: tgtg.Timestamp{Time: start},
+			End:   tgtg.Timestamp{Time: start.Add(time.Hour)},
+		},
+	}
+}
+
+var (
+	evening = time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)
+	morning = time.Date(2021, 12, 1, 8, 0, 0, 0, time.UTC)
+
+	testItems = []tgtg.Items{
+		entry("1", "Bakery Central", 399, 1200, 4.5, 1.2, 3, evening, "VEGETARIAN"),
+		entry("2", "Sushi Bar", 599, 1800, 3.9, 0.4, 0, evening),
+		entry("3", "Bakery North", 300, 600, 4.8, 2.5, 1, morning, "VEGAN"),
+		entry("4", "Grocery", 450, 1500, 4.5, 0.9, 5, morning),
+	}
+)
+
+func ids(items []tgtg.Items) []string {
+	result := make([]string, 0, len(items))
+	for _, entry := range items {
+		result = append(result, entry.Item.ItemID)
+	}
+	return result
+}
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		title      string
+		predicates []Predicate
+		expected   []string
+	}{
+		{title: "no predicates", expected: []string{"1", "2", "3", "4"}},
+		{title: "price between", predicates: []Predicate{PriceBetween(eur(300), eur(450))}, expected: []string{"1", "3", "4"}},
+		{title: "price in other currency", predicates: []Predicate{PriceBetween(tgtg.Price{Code: "GBP"}, tgtg.Price{Code: "GBP", MinorUnits: 1000})}, expected: []string{}},
+		{title: "min discount", predicates: []Predicate{MinDiscount(66.6)}, expected: []string{"1", "2", "4"}},
+		{title: "min rating", predicates: []Predicate{MinRating(4.5)}, expected: []string{"1", "3", "4"}},
+		{title: "max distance", predicates: []Predicate{MaxDistance(1)}, expected: []string{"2", "4"}},
+		{title: "pickup overlaps", predicates: []Predicate{PickupOverlaps(evening.Add(30*time.Minute), evening.Add(3*time.Hour))}, expected: []string{"1", "2"}},
+		{title: "store name", predicates: []Predicate{StoreName(regexp.MustCompile(`^Bakery`))}, expected: []string{"1", "3"}},
+		{title: "diet", predicates: []Predicate{Or(Diet(tgtg.DietCategoryVegan), Diet(tgtg.DietCategoryVegetarian))}, expected: []string{"1", "3"}},
+		{title: "in sales window and available", predicates: []Predicate{InSalesWindow(), Available()}, expected: []string{"1", "3", "4"}},
+		{title: "combined", predicates: []Predicate{Not(StoreName(regexp.MustCompile(`Bakery`))), MinRating(4)}, expected: []string{"4"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			actual := ids(Filter(testItems, tc.predicates...))
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Filter returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	testCases := []struct {
+		title    string
+		keys     []Key
+		expected []string
+	}{
+		{title: "no keys keeps order", expected: []string{"1", "2", "3", "4"}},
+		{title: "by price", keys: []Key{ByPrice}, expected: []string{"3", "1", "4", "2"}},
+		{title: "by distance", keys: []Key{ByDistance}, expected: []string{"2", "4", "1", "3"}},
+		{title: "by discount descending", keys: []Key{Desc(ByDiscount)}, expected: []string{"4", "1", "2", "3"}},
+		{title: "by rating descending, then distance", keys: []Key{Desc(ByRating), ByDistance}, expected: []string{"3", "4", "1", "2"}},
+		{title: "by pickup start, then store name", keys: []Key{ByPickupStart, ByStoreName}, expected: []string{"3", "4", "1", "2"}},
+		{title: "by available descending", keys: []Key{Desc(ByAvailable)}, expected: []string{"4", "1", "3", "2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			items := append([]tgtg.Items(nil), testItems...)
+			Sort(items, tc.keys...)
+			if actual := ids(items); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Sort returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
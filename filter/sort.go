@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"sort"
+	"strings"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// Key compares two entries, returning negative number if a goes before b, positive if after and zero if equal.
+type Key func(a, b *tgtg.Items) int
+
+// Sort sorts entries in place by keys, each next key breaking ties of previous ones.
+// Sort is stable, so entries equal by all keys keep their order.
+func Sort(items []tgtg.Items, keys ...Key) {
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range keys {
+			if c := key(&items[i], &items[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// Desc reverses order of key.
+func Desc(key Key) Key {
+	return func(a, b *tgtg.Items) int {
+		return key(b, a)
+	}
+}
+
+// ByPrice orders entries by ascending Item.PriceIncludingTaxes. Prices in different currencies
+// are ordered by currency code.
+func ByPrice(a, b *tgtg.Items) int {
+	pa, pb := a.Item.PriceIncludingTaxes, b.Item.PriceIncludingTaxes
+	c, err := pa.Cmp(pb)
+	if err != nil {
+		return strings.Compare(pa.Code, pb.Code)
+	}
+	return c
+}
+
+// ByDiscount orders entries by ascending discount, see tgtg.Item.Discount.
+func ByDiscount(a, b *tgtg.Items) int {
+	da, _ := a.Item.Discount()
+	db, _ := b.Item.Discount()
+	return compareFloat(da, db)
+}
+
+// ByRating orders entries by ascending average overall rating.
+func ByRating(a, b *tgtg.Items) int {
+	return compareFloat(a.Item.AverageOverallRating.AverageOverallRating, b.Item.AverageOverallRating.AverageOverallRating)
+}
+
+// ByDistance orders entries by ascending distance.
+func ByDistance(a, b *tgtg.Items) int {
+	return compareFloat(a.Distance, b.Distance)
+}
+
+// ByPickupStart orders entries by ascending pickup interval start.
+func ByPickupStart(a, b *tgtg.Items) int {
+	sa, sb := a.PickupInterval.Start, b.PickupInterval.Start
+	switch {
+	case sa.Before(sb.Time):
+		return -1
+	case sa.After(sb.Time):
+		return 1
+	}
+	return 0
+}
+
+// ByAvailable orders entries by ascending number of available items.
+func ByAvailable(a, b *tgtg.Items) int {
+	return a.ItemsAvailable - b.ItemsAvailable
+}
+
+// ByStoreName orders entries alphabetically by store name.
+func ByStoreName(a, b *tgtg.Items) int {
+	return strings.Compare(a.Store.StoreName, b.Store.StoreName)
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
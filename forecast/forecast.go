@@ -0,0 +1,181 @@
+// Package forecast estimates when Too Good To Go stores release bags, based on item snapshots
+// recorded by history package, and builds adaptive polling schedules around expected drops.
+//
+//	timeline, err := db.ItemTimeline(itemID, time.Now().AddDate(0, 0, -28), time.Time{})
+//	...
+//	prediction, err := forecast.Predict(timeline)
+//	...
+//	schedule := forecast.NewSchedule(nil, prediction)
+//	time.Sleep(schedule.Interval(time.Now()))
+package forecast
+
+import (
+	"sort"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"github.com/filippalach/tgt-go/history"
+)
+
+// Drop represents single observed release of bags: availability going from zero to positive.
+type Drop struct {
+	// At is time of the first snapshot with bags available, in store's time zone.
+	At time.Time
+
+	// Available is number of bags available at the drop.
+	Available int
+
+	// SoldOutAfter is time it took to sell out all bags, zero if not observed.
+	SoldOutAfter time.Duration
+}
+
+// ReleaseTimes describes distribution of drop times on a weekday, as durations since local midnight.
+type ReleaseTimes struct {
+	// Count is number of observed drops.
+	Count int
+
+	// Earliest, Median and Latest observed drop times.
+	Earliest time.Duration
+	Median   time.Duration
+	Latest   time.Duration
+}
+
+// Prediction describes release pattern of single item.
+type Prediction struct {
+	ItemID   string
+	Location *time.Location
+
+	// Drops lists all observed drops, ordered by time.
+	Drops []Drop
+
+	// Weekdays holds release time distribution, indexed by time.Weekday, in store's time zone.
+	Weekdays [7]ReleaseTimes
+
+	// SellOut is median time it took to sell out all bags, zero if no sell-out was observed.
+	SellOut time.Duration
+}
+
+// Predict estimates release pattern of single item from its snapshots, ordered by observation time
+// as returned by history.DB.ItemTimeline. Drop is detected only when availability of zero was observed
+// before, therefore first snapshot never counts as a drop.
+func Predict(timeline []history.Snapshot) (*Prediction, error) {
+	prediction := &Prediction{Location: time.UTC}
+	if len(timeline) == 0 {
+		return prediction, nil
+	}
+
+	prediction.ItemID = timeline[0].ItemID
+	location, err := time.LoadLocation(timeline[0].StoreTimeZone)
+	if err != nil {
+		return nil, tgtg.NewArgumentError("timeline", "invalid store time zone: "+err.Error())
+	}
+	prediction.Location = location
+
+	var current *Drop
+	for i := 1; i < len(timeline); i++ {
+		previous, snapshot := timeline[i-1], timeline[i]
+
+		switch {
+		case previous.ItemsAvailable == 0 && snapshot.ItemsAvailable > 0:
+			prediction.Drops = append(prediction.Drops, Drop{
+				At:        snapshot.ObservedAt.In(location),
+				Available: snapshot.ItemsAvailable,
+			})
+			current = &prediction.Drops[len(prediction.Drops)-1]
+		case previous.ItemsAvailable > 0 && snapshot.ItemsAvailable == 0 && current != nil:
+			soldOutAt := snapshot.ObservedAt.In(location)
+			// Bags not sold by the end of the day are withdrawn, which is not a sell-out.
+			if !sameDay(current.At, soldOutAt) {
+				current = nil
+				continue
+			}
+			if !snapshot.SoldOutAt.IsZero() && snapshot.SoldOutAt.After(current.At) && snapshot.SoldOutAt.Before(soldOutAt) {
+				soldOutAt = snapshot.SoldOutAt
+			}
+			current.SoldOutAfter = soldOutAt.Sub(current.At)
+			current = nil
+		}
+	}
+
+	var byWeekday [7][]time.Duration
+	var sellOuts []time.Duration
+	for _, drop := range prediction.Drops {
+		byWeekday[drop.At.Weekday()] = append(byWeekday[drop.At.Weekday()], sinceMidnight(drop.At))
+		if drop.SoldOutAfter > 0 {
+			sellOuts = append(sellOuts, drop.SoldOutAfter)
+		}
+	}
+
+	for weekday, times := range byWeekday {
+		if len(times) == 0 {
+			continue
+		}
+		sortDurations(times)
+		prediction.Weekdays[weekday] = ReleaseTimes{
+			Count:    len(times),
+			Earliest: times[0],
+			Median:   median(times),
+			Latest:   times[len(times)-1],
+		}
+	}
+
+	if len(sellOuts) > 0 {
+		sortDurations(sellOuts)
+		prediction.SellOut = median(sellOuts)
+	}
+
+	return prediction, nil
+}
+
+// NextDrop returns expected time of the next drop after now, based on median release time
+// of following weekdays. Returns false if no drops were observed.
+func (p *Prediction) NextDrop(now time.Time) (time.Time, bool) {
+	local := now.In(p.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, p.Location)
+
+	for day := 0; day <= 7; day++ {
+		date := midnight.AddDate(0, 0, day)
+		release := p.Weekdays[date.Weekday()]
+		if release.Count == 0 {
+			continue
+		}
+
+		at := atTimeOfDay(date, release.Median)
+		if at.After(now) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sinceMidnight returns time elapsed since local midnight of t.
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// sameDay reports whether a and b, in the same location, fall on the same date.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// atTimeOfDay returns time on the date of midnight, offset by d of wall clock time.
+// Wall clock is used to stay correct across DST changes.
+func atTimeOfDay(midnight time.Time, d time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, 0, 0, int(d), midnight.Location())
+}
+
+func sortDurations(durations []time.Duration) {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+}
+
+// median returns median of sorted durations.
+func median(sorted []time.Duration) time.Duration {
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
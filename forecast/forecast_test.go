@@ -0,0 +1,126 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/filippalach/tgt-go/history"
+)
+
+func snapshot(at time.Time, available int) history.Snapshot {
+	return history.Snapshot{ItemID: "1", StoreTimeZone: "Europe/Warsaw", ObservedAt: at, ItemsAvailable: available}
+}
+
+func utc(day, hour, minute int) time.Time {
+	return time.Date(2021, 12, day, hour, minute, 0, 0, time.UTC)
+}
+
+// timeline has drops on Wednesdays at 18:05 and 18:15, and on Thursday at 09:00, Warsaw time.
+var timeline = []history.Snapshot{
+	snapshot(utc(1, 17, 0), 0),
+	snapshot(utc(1, 17, 5), 3),
+	snapshot(utc(1, 17, 35), 0),
+	snapshot(utc(2, 7, 55), 0),
+	snapshot(utc(2, 8, 0), 5),
+	snapshot(utc(8, 16, 55), 0),
+	snapshot(utc(8, 17, 15), 2),
+	snapshot(utc(8, 17, 35), 0),
+}
+
+func TestPredict(t *testing.T) {
+	prediction, err := Predict(timeline)
+	if err != nil {
+		t.Fatalf("Predict returned error: %+v", err)
+	}
+
+	if len(prediction.Drops) != 3 {
+		t.Fatalf("Predict returned drops: %+v, expected 3", prediction.Drops)
+	}
+	if actual := prediction.Drops[1].SoldOutAfter; actual != 0 {
+		t.Errorf("Drop.SoldOutAfter: %+v, expected: 0 for bags withdrawn next day", actual)
+	}
+
+	expected := ReleaseTimes{
+		Count:    2,
+		Earliest: 18*time.Hour + 5*time.Minute,
+		Median:   18*time.Hour + 10*time.Minute,
+		Latest:   18*time.Hour + 15*time.Minute,
+	}
+	if actual := prediction.Weekdays[time.Wednesday]; actual != expected {
+		t.Errorf("Prediction.Weekdays[Wednesday]: %+v, expected: %+v", actual, expected)
+	}
+	if actual := prediction.Weekdays[time.Thursday]; actual.Count != 1 || actual.Median != 9*time.Hour {
+		t.Errorf("Prediction.Weekdays[Thursday]: %+v, expected single drop at 9h", actual)
+	}
+	if actual := prediction.SellOut; actual != 25*time.Minute {
+		t.Errorf("Prediction.SellOut: %+v, expected: %+v", actual, 25*time.Minute)
+	}
+
+	_, err = Predict([]history.Snapshot{{StoreTimeZone: "Not/AZone"}})
+	if err == nil {
+		t.Error("Predict returned no error for invalid time zone.")
+	}
+}
+
+func TestPrediction_NextDrop(t *testing.T) {
+	prediction, err := Predict(timeline)
+	if err != nil {
+		t.Fatalf("Predict returned error: %+v", err)
+	}
+
+	testCases := []struct {
+		title    string
+		now      time.Time
+		expected time.Time
+	}{
+		{title: "later same day", now: utc(15, 12, 0), expected: utc(15, 17, 10)},
+		{title: "next day", now: utc(15, 18, 0), expected: utc(16, 8, 0)},
+		{title: "next week", now: utc(16, 9, 0), expected: utc(22, 17, 10)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			actual, ok := prediction.NextDrop(tc.now)
+			if !ok || !actual.Equal(tc.expected) {
+				t.Errorf("Prediction.NextDrop returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+
+	if _, ok := (&Prediction{Location: time.UTC}).NextDrop(utc(15, 12, 0)); ok {
+		t.Error("Prediction.NextDrop returned drop for prediction without drops.")
+	}
+}
+
+func TestSchedule_Interval(t *testing.T) {
+	prediction, err := Predict(timeline)
+	if err != nil {
+		t.Fatalf("Predict returned error: %+v", err)
+	}
+	schedule := NewSchedule(&ScheduleOptions{Fast: time.Minute, Slow: time.Hour}, prediction)
+
+	testCases := []struct {
+		title    string
+		now      time.Time
+		expected time.Duration
+	}{
+		{title: "far from drop", now: utc(15, 12, 0), expected: time.Hour},
+		{title: "approaching drop", now: utc(15, 16, 35), expected: 20 * time.Minute},
+		{title: "within lead", now: utc(15, 16, 56), expected: time.Minute},
+		{title: "within tail", now: utc(15, 17, 24), expected: time.Minute},
+		{title: "after drop", now: utc(15, 17, 26), expected: time.Hour},
+		{title: "just before window", now: utc(15, 16, 54).Add(30 * time.Second), expected: time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := schedule.Interval(tc.now); actual != tc.expected {
+				t.Errorf("Schedule.Interval returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+
+	if actual := NewSchedule(nil).Interval(utc(15, 12, 0)); actual != 15*time.Minute {
+		t.Errorf("Schedule.Interval returned: %+v, expected: %+v", actual, 15*time.Minute)
+	}
+}
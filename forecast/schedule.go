@@ -0,0 +1,98 @@
+package forecast
+
+import "time"
+
+// ScheduleOptions specifies polling intervals of Schedule.
+type ScheduleOptions struct {
+	// Fast is polling interval around expected drops. Defaults to 30 seconds.
+	Fast time.Duration
+
+	// Slow is polling interval outside of expected drops. Defaults to 15 minutes.
+	Slow time.Duration
+
+	// Lead is how long before earliest observed drop time fast polling starts. Defaults to 10 minutes.
+	Lead time.Duration
+
+	// Tail is how long after latest observed drop time fast polling continues. Defaults to 10 minutes.
+	Tail time.Duration
+}
+
+// Schedule is an adaptive polling schedule, polling fast around expected drops of predicted items
+// and slow otherwise. It is safe for concurrent use.
+type Schedule struct {
+	options     ScheduleOptions
+	predictions []*Prediction
+}
+
+// NewSchedule returns Schedule covering drops of all given predictions. Nil options use defaults.
+func NewSchedule(options *ScheduleOptions, predictions ...*Prediction) *Schedule {
+	opts := ScheduleOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Fast <= 0 {
+		opts.Fast = 30 * time.Second
+	}
+	if opts.Slow <= 0 {
+		opts.Slow = 15 * time.Minute
+	}
+	if opts.Lead <= 0 {
+		opts.Lead = 10 * time.Minute
+	}
+	if opts.Tail <= 0 {
+		opts.Tail = 10 * time.Minute
+	}
+
+	return &Schedule{options: opts, predictions: predictions}
+}
+
+// Interval returns how long to wait before next poll. Within drop windows it returns fast interval,
+// outside of them slow interval, shortened so that no drop window is skipped.
+func (s *Schedule) Interval(now time.Time) time.Duration {
+	interval := s.options.Slow
+	for _, prediction := range s.predictions {
+		start, end, ok := s.window(prediction, now)
+		if !ok {
+			continue
+		}
+		if !now.Before(start) && now.Before(end) {
+			return s.options.Fast
+		}
+		if until := start.Sub(now); until < interval {
+			interval = until
+		}
+	}
+
+	if interval < s.options.Fast {
+		interval = s.options.Fast
+	}
+	return interval
+}
+
+// Next returns time of the next poll after now.
+func (s *Schedule) Next(now time.Time) time.Time {
+	return now.Add(s.Interval(now))
+}
+
+// window returns current or next drop window of prediction: from Lead before earliest
+// to Tail after latest drop time of the day. Returns false if no drops were observed.
+func (s *Schedule) window(prediction *Prediction, now time.Time) (time.Time, time.Time, bool) {
+	local := now.In(prediction.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, prediction.Location)
+
+	// Starting a day before covers windows spanning midnight.
+	for day := -1; day <= 7; day++ {
+		date := midnight.AddDate(0, 0, day)
+		release := prediction.Weekdays[date.Weekday()]
+		if release.Count == 0 {
+			continue
+		}
+
+		start := atTimeOfDay(date, release.Earliest).Add(-s.options.Lead)
+		end := atTimeOfDay(date, release.Latest).Add(s.options.Tail)
+		if end.After(now) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
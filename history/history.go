@@ -0,0 +1,407 @@
+// Package history records snapshots of Too Good To Go items over time in an embedded,
+// pure Go bbolt database, allowing to analyse when bags appear and sell out.
+//
+//	db, err := history.Open("history.db", &history.Options{Retention: 90 * 24 * time.Hour})
+//	...
+//	response, _, err := client.Items.List(ctx, request)
+//	...
+//	err = db.RecordItems(response.Items, time.Now())
+//	timeline, err := db.ItemTimeline(itemID, from, to)
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	itemsBucket  = []byte("items")
+	storesBucket = []byte("stores")
+)
+
+// Snapshot represents state of single item at a point in time.
+type Snapshot struct {
+	ItemID        string    `json:"item_id"`
+	StoreID       string    `json:"store_id"`
+	StoreName     string    `json:"store_name"`
+	StoreTimeZone string    `json:"store_time_zone"`
+	DisplayName   string    `json:"display_name"`
+	ObservedAt    time.Time `json:"observed_at"`
+
+	ItemsAvailable int        `json:"items_available"`
+	InSalesWindow  bool       `json:"in_sales_window"`
+	Price          tgtg.Price `json:"price"`
+
+	PickupStart time.Time `json:"pickup_start"`
+	PickupEnd   time.Time `json:"pickup_end"`
+	PurchaseEnd time.Time `json:"purchase_end"`
+	SoldOutAt   time.Time `json:"sold_out_at"`
+}
+
+// FromItems returns Snapshot of items entry returned by Items.List, observed at given time.
+func FromItems(items tgtg.Items, observedAt time.Time) Snapshot {
+	return Snapshot{
+		ItemID:         items.Item.ItemID,
+		StoreID:        items.Store.StoreID,
+		StoreName:      items.Store.StoreName,
+		StoreTimeZone:  items.Store.StoreTimeZone,
+		DisplayName:    items.DisplayName,
+		ObservedAt:     observedAt,
+		ItemsAvailable: items.ItemsAvailable,
+		InSalesWindow:  items.InSalesWindow,
+		Price:          items.Item.PriceIncludingTaxes,
+		PickupStart:    items.PickupInterval.Start.Time,
+		PickupEnd:      items.PickupInterval.End.Time,
+		PurchaseEnd:    items.PurchaseEnd.Time,
+		SoldOutAt:      items.SoldOutAt.Time,
+	}
+}
+
+// FromItemResponse returns Snapshot of item returned by Items.Get, observed at given time.
+func FromItemResponse(response *tgtg.GetItemResponse, observedAt time.Time) Snapshot {
+	return Snapshot{
+		ItemID:         response.Item.ItemID,
+		StoreID:        response.Store.StoreID,
+		StoreName:      response.Store.StoreName,
+		StoreTimeZone:  response.Store.StoreTimeZone,
+		DisplayName:    response.DisplayName,
+		ObservedAt:     observedAt,
+		ItemsAvailable: response.ItemsAvailable,
+		InSalesWindow:  response.InSalesWindow,
+		Price:          response.Item.PriceIncludingTaxes,
+		PickupStart:    response.PickupInterval.Start.Time,
+		PickupEnd:      response.PickupInterval.End.Time,
+		PurchaseEnd:    response.PurchaseEnd.Time,
+	}
+}
+
+// sameState reports whether snapshots differ only by observation time. Times are compared as instants,
+// as snapshots decoded from the database do not share locations with fresh ones.
+func (s Snapshot) sameState(other Snapshot) bool {
+	return s.ItemID == other.ItemID &&
+		s.StoreID == other.StoreID &&
+		s.StoreName == other.StoreName &&
+		s.StoreTimeZone == other.StoreTimeZone &&
+		s.DisplayName == other.DisplayName &&
+		s.ItemsAvailable == other.ItemsAvailable &&
+		s.InSalesWindow == other.InSalesWindow &&
+		s.Price == other.Price &&
+		s.PickupStart.Equal(other.PickupStart) &&
+		s.PickupEnd.Equal(other.PickupEnd) &&
+		s.PurchaseEnd.Equal(other.PurchaseEnd) &&
+		s.SoldOutAt.Equal(other.SoldOutAt)
+}
+
+// Options specifies options of DB.
+type Options struct {
+	// Retention specifies for how long snapshots are kept by ApplyRetention. Zero keeps them forever.
+	Retention time.Duration
+
+	// CompactAfter specifies age after which snapshots are compacted by ApplyRetention. Zero disables compaction.
+	CompactAfter time.Duration
+
+	// Now returns current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// DB stores item snapshots. It is safe for concurrent use.
+type DB struct {
+	db      *bolt.DB
+	options Options
+}
+
+// Open opens or creates database at path.
+func Open(path string, options *Options) (*DB, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	opts := *options
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{itemsBucket, storesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &DB{db: db, options: opts}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Record stores snapshots. Snapshot of the same item observed at the same time is overwritten.
+func (d *DB) Record(snapshots ...Snapshot) error {
+	for _, snapshot := range snapshots {
+		if snapshot.ItemID == "" {
+			return errors.New("history: snapshot has no item id")
+		}
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		for _, snapshot := range snapshots {
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				return err
+			}
+
+			items, err := tx.Bucket(itemsBucket).CreateBucketIfNotExists([]byte(snapshot.ItemID))
+			if err != nil {
+				return err
+			}
+			if err := items.Put(timeKey(snapshot.ObservedAt), data); err != nil {
+				return err
+			}
+
+			if snapshot.StoreID == "" {
+				continue
+			}
+			stores, err := tx.Bucket(storesBucket).CreateBucketIfNotExists([]byte(snapshot.StoreID))
+			if err != nil {
+				return err
+			}
+			if err := stores.Put(storeKey(snapshot.ObservedAt, snapshot.ItemID), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordItems stores snapshots of all items entries returned by Items.List, observed at given time.
+func (d *DB) RecordItems(items []tgtg.Items, observedAt time.Time) error {
+	snapshots := make([]Snapshot, 0, len(items))
+	for _, entry := range items {
+		snapshots = append(snapshots, FromItems(entry, observedAt))
+	}
+	return d.Record(snapshots...)
+}
+
+// ItemTimeline returns snapshots of item observed within [from, to), ordered by observation time.
+// Zero to means no upper bound.
+func (d *DB) ItemTimeline(itemID string, from, to time.Time) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	err := d.db.View(func(tx *bolt.Tx) error {
+		items := tx.Bucket(itemsBucket).Bucket([]byte(itemID))
+		if items == nil {
+			return nil
+		}
+
+		cursor := items.Cursor()
+		for k, v := cursor.Seek(timeKey(from)); k != nil && before(k, to); k, v = cursor.Next() {
+			var snapshot Snapshot
+			if err := json.Unmarshal(v, &snapshot); err != nil {
+				return err
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	})
+	return snapshots, err
+}
+
+// StoreTimeline returns snapshots of all items of store observed within [from, to), ordered by observation time.
+// Zero to means no upper bound.
+func (d *DB) StoreTimeline(storeID string, from, to time.Time) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	err := d.db.View(func(tx *bolt.Tx) error {
+		stores := tx.Bucket(storesBucket).Bucket([]byte(storeID))
+		if stores == nil {
+			return nil
+		}
+
+		cursor := stores.Cursor()
+		for k, _ := cursor.Seek(timeKey(from)); k != nil && before(k, to); k, _ = cursor.Next() {
+			items := tx.Bucket(itemsBucket).Bucket(k[8:])
+			if items == nil {
+				continue
+			}
+			v := items.Get(k[:8])
+			if v == nil {
+				continue
+			}
+
+			var snapshot Snapshot
+			if err := json.Unmarshal(v, &snapshot); err != nil {
+				return err
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	})
+	return snapshots, err
+}
+
+// ItemIDs returns IDs of all recorded items.
+func (d *DB) ItemIDs() ([]string, error) {
+	var ids []string
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).ForEach(func(k, v []byte) error {
+			ids = append(ids, string(k))
+			return nil
+		})
+	})
+	return ids, err
+}
+
+// Prune deletes snapshots observed before given time, returning number of deleted snapshots.
+func (d *DB) Prune(before time.Time) (int, error) {
+	deleted := 0
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		limit := timeKey(before)
+
+		err := forEachBucket(tx.Bucket(itemsBucket), func(items *bolt.Bucket) error {
+			cursor := items.Cursor()
+			for k, _ := cursor.First(); k != nil && string(k) < string(limit); k, _ = cursor.First() {
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+				deleted++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return forEachBucket(tx.Bucket(storesBucket), func(stores *bolt.Bucket) error {
+			cursor := stores.Cursor()
+			for k, _ := cursor.First(); k != nil && string(k[:8]) < string(limit); k, _ = cursor.First() {
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	return deleted, err
+}
+
+// Compact deletes snapshots observed before given time which do not change item state compared
+// to the previous snapshot, keeping only state transitions. Returns number of deleted snapshots.
+func (d *DB) Compact(before time.Time) (int, error) {
+	deleted := 0
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		limit := timeKey(before)
+		stores := tx.Bucket(storesBucket)
+
+		return forEachBucket(tx.Bucket(itemsBucket), func(items *bolt.Bucket) error {
+			var previous *Snapshot
+			var redundant [][]byte
+
+			cursor := items.Cursor()
+			for k, v := cursor.First(); k != nil && string(k) < string(limit); k, v = cursor.Next() {
+				var snapshot Snapshot
+				if err := json.Unmarshal(v, &snapshot); err != nil {
+					return err
+				}
+				if previous != nil && previous.sameState(snapshot) {
+					redundant = append(redundant, append([]byte(nil), k...))
+					if index := stores.Bucket([]byte(snapshot.StoreID)); index != nil {
+						if err := index.Delete(storeKey(snapshot.ObservedAt, snapshot.ItemID)); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				previous = &snapshot
+			}
+
+			for _, k := range redundant {
+				if err := items.Delete(k); err != nil {
+					return err
+				}
+				deleted++
+			}
+			return nil
+		})
+	})
+	return deleted, err
+}
+
+// ApplyRetention prunes and compacts snapshots according to Options, returning number of deleted snapshots.
+func (d *DB) ApplyRetention() (int, error) {
+	now := d.options.Now()
+	total := 0
+
+	if d.options.Retention > 0 {
+		deleted, err := d.Prune(now.Add(-d.options.Retention))
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+	}
+
+	if d.options.CompactAfter > 0 {
+		deleted, err := d.Compact(now.Add(-d.options.CompactAfter))
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+	}
+
+	return total, nil
+}
+
+// forEachBucket calls fn for every nested bucket of parent.
+func forEachBucket(parent *bolt.Bucket, fn func(*bolt.Bucket) error) error {
+	var names [][]byte
+	err := parent.ForEach(func(k, v []byte) error {
+		if v == nil {
+			names = append(names, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := fn(parent.Bucket(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeKey encodes time as sortable big endian Unix nanoseconds. Times before 1970 are clamped to zero.
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	nanos := int64(0)
+	if !t.IsZero() && t.UnixNano() > 0 {
+		nanos = t.UnixNano()
+	}
+	binary.BigEndian.PutUint64(key, uint64(nanos))
+	return key
+}
+
+// storeKey encodes store index key: observation time followed by item ID.
+func storeKey(t time.Time, itemID string) []byte {
+	return append(timeKey(t), itemID...)
+}
+
+// before reports whether key's time is before t. Zero t means no bound.
+func before(key []byte, t time.Time) bool {
+	return t.IsZero() || string(key[:8]) < string(timeKey(t))
+}
@@ -0,0 +1,227 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func openTestDB(t *testing.T, options *Options) *DB {
+	db, err := Open(filepath.Join(t.TempDir(), "history.db"), options)
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func at(minute int) time.Time {
+	return time.Date(2021, 12, 1, 18, minute, 0, 0, time.UTC)
+}
+
+func snapshot(itemID, storeID string, minute, available int) Snapshot {
+	return Snapshot{ItemID: itemID, StoreID: storeID, ObservedAt: at(minute), ItemsAvailable: available}
+}
+
+func availability(snapshots []Snapshot) []int {
+	var actual []int
+	for _, s := range snapshots {
+		actual = append(actual, s.ItemsAvailable)
+	}
+	return actual
+}
+
+func TestDB_ItemTimeline(t *testing.T) {
+	db := openTestDB(t, nil)
+
+	err := db.Record(
+		snapshot("1", "s1", 2, 3),
+		snapshot("1", "s1", 0, 5),
+		snapshot("1", "s1", 1, 4),
+		snapshot("2", "s1", 1, 9),
+	)
+	if err != nil {
+		t.Fatalf("DB.Record returned error: %+v", err)
+	}
+
+	testCases := []struct {
+		title    string
+		from, to time.Time
+		expected []int
+	}{
+		{title: "all", expected: []int{5, 4, 3}},
+		{title: "from", from: at(1), expected: []int{4, 3}},
+		{title: "to is exclusive", to: at(2), expected: []int{5, 4}},
+		{title: "empty range", from: at(3), expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			actual, err := db.ItemTimeline("1", tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("DB.ItemTimeline returned error: %+v", err)
+			}
+			if !reflect.DeepEqual(availability(actual), tc.expected) {
+				t.Errorf("DB.ItemTimeline returned: %+v, expected: %+v", availability(actual), tc.expected)
+			}
+		})
+	}
+
+	actual, err := db.ItemTimeline("unknown", time.Time{}, time.Time{})
+	if err != nil || actual != nil {
+		t.Errorf("DB.ItemTimeline returned: %+v, %+v, expected no snapshots", actual, err)
+	}
+}
+
+func TestDB_StoreTimeline(t *testing.T) {
+	db := openTestDB(t, nil)
+
+	err := db.Record(
+		snapshot("1", "s1", 0, 1),
+		snapshot("2", "s1", 1, 2),
+		snapshot("3", "s2", 1, 3),
+		snapshot("1", "s1", 2, 4),
+	)
+	if err != nil {
+		t.Fatalf("DB.Record returned error: %+v", err)
+	}
+
+	actual, err := db.StoreTimeline("s1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("DB.StoreTimeline returned error: %+v", err)
+	}
+	if expected := []int{1, 2, 4}; !reflect.DeepEqual(availability(actual), expected) {
+		t.Errorf("DB.StoreTimeline returned: %+v, expected: %+v", availability(actual), expected)
+	}
+}
+
+func TestDB_RecordItems(t *testing.T) {
+	db := openTestDB(t, nil)
+
+	items := []tgtg.Items{
+		{
+			Item:           tgtg.Item{ItemID: "1", PriceIncludingTaxes: tgtg.Price{Code: "PLN", MinorUnits: 999, Decimals: 2}},
+			Store:          tgtg.Store{StoreID: "s1", StoreName: "store"},
+			DisplayName:    "store - bag",
+			ItemsAvailable: 2,
+			InSalesWindow:  true,
+		},
+	}
+	if err := db.RecordItems(items, at(0)); err != nil {
+		t.Fatalf("DB.RecordItems returned error: %+v", err)
+	}
+
+	actual, err := db.ItemTimeline("1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("DB.ItemTimeline returned error: %+v", err)
+	}
+
+	expected := []Snapshot{
+		{
+			ItemID:         "1",
+			StoreID:        "s1",
+			StoreName:      "store",
+			DisplayName:    "store - bag",
+			ObservedAt:     at(0),
+			ItemsAvailable: 2,
+			InSalesWindow:  true,
+			Price:          tgtg.Price{Code: "PLN", MinorUnits: 999, Decimals: 2},
+		},
+	}
+	if len(actual) != 1 || !actual[0].ObservedAt.Equal(at(0)) {
+		t.Fatalf("DB.ItemTimeline returned: %+v, expected: %+v", actual, expected)
+	}
+	actual[0].ObservedAt = at(0)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("DB.ItemTimeline returned: %+v, expected: %+v", actual, expected)
+	}
+
+	if err := db.Record(Snapshot{}); err == nil {
+		t.Error("DB.Record returned no error for snapshot without item id.")
+	}
+}
+
+func TestDB_ApplyRetention(t *testing.T) {
+	db := openTestDB(t, &Options{
+		Retention:    50 * time.Minute,
+		CompactAfter: 30 * time.Minute,
+		Now:          func() time.Time { return at(60) },
+	})
+
+	err := db.Record(
+		snapshot("1", "s1", 0, 5),  // pruned
+		snapshot("1", "s1", 10, 5), // kept, first after pruning
+		snapshot("1", "s1", 15, 5), // compacted
+		snapshot("1", "s1", 20, 3), // kept, state changed
+		snapshot("1", "s1", 25, 3), // compacted
+		snapshot("1", "s1", 40, 3), // kept, not old enough
+	)
+	if err != nil {
+		t.Fatalf("DB.Record returned error: %+v", err)
+	}
+
+	deleted, err := db.ApplyRetention()
+	if err != nil {
+		t.Fatalf("DB.ApplyRetention returned error: %+v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("DB.ApplyRetention deleted: %d, expected: 3", deleted)
+	}
+
+	actual, err := db.ItemTimeline("1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("DB.ItemTimeline returned error: %+v", err)
+	}
+	var minutes []int
+	for _, s := range actual {
+		minutes = append(minutes, s.ObservedAt.Minute())
+	}
+	if expected := []int{10, 20, 40}; !reflect.DeepEqual(minutes, expected) {
+		t.Errorf("DB.ItemTimeline returned snapshots at: %+v, expected: %+v", minutes, expected)
+	}
+
+	stores, err := db.StoreTimeline("s1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("DB.StoreTimeline returned error: %+v", err)
+	}
+	if len(stores) != 3 {
+		t.Errorf("DB.StoreTimeline returned %d snapshots, expected: 3", len(stores))
+	}
+}
+
+func TestDB_CompactOffsetTimes(t *testing.T) {
+	db := openTestDB(t, nil)
+
+	// Pickup times as parsed from API timestamps with offset. Offset is not a whole hour, whose zones are cached.
+	pickupStart, err := time.Parse(time.RFC3339, "2021-12-01T23:30:00+05:30")
+	if err != nil {
+		t.Fatalf("time.Parse returned error: %+v", err)
+	}
+	fresh := func(minute int) Snapshot {
+		s := snapshot("1", "s1", minute, 2)
+		s.PickupStart, s.PickupEnd = pickupStart, pickupStart.Add(time.Hour)
+		return s
+	}
+
+	if err := db.Record(fresh(0), fresh(10)); err != nil {
+		t.Fatalf("DB.Record returned error: %+v", err)
+	}
+	stored, err := db.ItemTimeline("1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("DB.ItemTimeline returned error: %+v", err)
+	}
+	if len(stored) != 2 || !stored[0].sameState(fresh(20)) {
+		t.Errorf("Snapshot.sameState returned false for stored: %+v and fresh: %+v", stored, fresh(20))
+	}
+
+	deleted, err := db.Compact(at(30))
+	if err != nil {
+		t.Fatalf("DB.Compact returned error: %+v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DB.Compact deleted: %d, expected: 1", deleted)
+	}
+}
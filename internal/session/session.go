@@ -0,0 +1,233 @@
+// Package session holds authenticated Too Good To Go client of commands, persisting its tokens
+// to a file and refreshing them before they expire.
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+const (
+	// refreshMargin is how long before access token expiry it is refreshed.
+	refreshMargin = 5 * time.Minute
+
+	// retryInterval is how long to wait before retrying failed refresh.
+	retryInterval = time.Minute
+
+	// defaultTokenTTL is assumed access token TTL when API does not return one.
+	defaultTokenTTL = time.Hour
+)
+
+// sessionFile is persisted form of session.
+type sessionFile struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	UserID       string    `json:"user_id"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Session holds authenticated client, persisting its tokens to a file and refreshing them before they expire.
+// Refresh swaps the client for an authenticated clone, so requests in flight are not affected.
+// Session is safe for concurrent use.
+type Session struct {
+	mu     sync.RWMutex
+	client *tgtg.Client
+	file   sessionFile
+
+	// refreshMu serializes refreshes, so that rotated refresh token is never spent twice.
+	refreshMu sync.Mutex
+
+	path string
+	now  func() time.Time
+}
+
+// Load returns Session of base client with tokens read from file at path.
+// Missing file results in unauthenticated Session.
+func Load(base *tgtg.Client, path string) (*Session, error) {
+	s := &Session{client: base, path: path, now: time.Now}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.file); err != nil {
+		return nil, err
+	}
+	s.client = base.WithAuth(s.file.AccessToken, s.file.RefreshToken, s.file.UserID)
+	return s, nil
+}
+
+// Client returns current authenticated client.
+func (s *Session) Client() *tgtg.Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.client
+}
+
+// Authenticated reports whether session holds tokens.
+func (s *Session) Authenticated() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.file.RefreshToken != ""
+}
+
+// ExpiresAt returns expiry time of access token.
+func (s *Session) ExpiresAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.file.ExpiresAt
+}
+
+// Login authenticates session with email, waiting until login is confirmed using link sent in email.
+func (s *Session) Login(ctx context.Context, email, deviceType string, pollInterval time.Duration) error {
+	client := s.Client().Clone()
+
+	login, _, err := client.Auth.Login(ctx, &tgtg.LoginRequest{DeviceType: deviceType, Email: email})
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		poll, _, err := client.Auth.Poll(ctx, &tgtg.PollRequest{DeviceType: deviceType, Email: email, PollingID: login.PollingID})
+		// Login not confirmed yet, empty body is returned.
+		if errors.Is(err, io.EOF) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.Set(client, time.Duration(poll.AccessTokenTTL)*time.Second)
+	}
+}
+
+// Refresh refreshes session tokens. Concurrent refreshes are serialized.
+func (s *Session) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	return s.refresh(ctx)
+}
+
+// RefreshRejected refreshes session tokens after Too Good To Go API rejected given access token,
+// unless session tokens have changed since, e.g. refreshed by a concurrent caller whose request
+// was rejected too. Either way, Client returns client with fresh tokens afterwards.
+func (s *Session) RefreshRejected(ctx context.Context, accessToken string) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if s.Client().AccessToken != accessToken {
+		return nil
+	}
+	return s.refresh(ctx)
+}
+
+// refresh refreshes session tokens, refreshMu has to be held.
+func (s *Session) refresh(ctx context.Context) error {
+	client := s.Client().Clone()
+
+	refresh, _, err := client.Auth.Refresh(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.Set(client, time.Duration(refresh.AccessTokenTTL)*time.Second)
+}
+
+// Run refreshes tokens shortly before they expire, until ctx is done.
+func (s *Session) Run(ctx context.Context, logger *log.Logger) {
+	for {
+		wait := s.ExpiresAt().Add(-refreshMargin).Sub(s.now())
+		if wait < 0 {
+			wait = 0
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.Refresh(ctx); err != nil {
+			logger.Printf("refreshing tokens failed, retrying in %s: %v", retryInterval, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+		logger.Printf("tokens refreshed, valid until %s", s.ExpiresAt().Format(time.RFC3339))
+	}
+}
+
+// Set swaps session client for authenticated one, with access token valid for ttl, and persists its tokens.
+// Non-positive ttl is assumed to be an hour.
+func (s *Session) Set(client *tgtg.Client, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = defaultTokenTTL
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.client = client
+	s.file = sessionFile{
+		AccessToken:  client.AccessToken,
+		RefreshToken: client.RefreshToken,
+		UserID:       client.UserID,
+		ExpiresAt:    s.now().Add(ttl),
+	}
+	return s.save()
+}
+
+// save atomically writes session file, readable only by the owner.
+func (s *Session) save() error {
+	data, err := json.MarshalIndent(s.file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
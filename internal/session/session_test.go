@@ -0,0 +1,166 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// setupUpstream returns client configured to call test Too Good To Go API server.
+func setupUpstream(t *testing.T) (*tgtg.Client, *http.ServeMux) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := tgtg.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client, mux
+}
+
+func TestSession_Refresh(t *testing.T) {
+	client, mux := setupUpstream(t)
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "new_access", "refresh_token": "new_refresh", "access_token_ttl_seconds": 3600}`)
+	})
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := os.WriteFile(path, []byte(`{"access_token": "access", "refresh_token": "refresh", "user_id": "1"}`), 0600); err != nil {
+		t.Fatalf("Write session file: %+v", err)
+	}
+
+	s, err := Load(client, path)
+	if err != nil {
+		t.Fatalf("Load returned error: %+v", err)
+	}
+	if !s.Authenticated() || s.Client().AccessToken != "access" {
+		t.Fatalf("Load returned session with client: %+v, expected persisted tokens", s.Client())
+	}
+
+	now := time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	previous := s.Client()
+
+	if err := s.Refresh(context.Background()); err != nil {
+		t.Fatalf("Session.Refresh returned error: %+v", err)
+	}
+
+	if actual := s.Client(); actual.AccessToken != "new_access" || actual.RefreshToken != "new_refresh" || actual.UserID != "1" {
+		t.Errorf("Session.Client returned: %+v, expected refreshed tokens", actual)
+	}
+	if previous.AccessToken != "access" {
+		t.Errorf("Previous client access token: %+v, expected it not to change", previous.AccessToken)
+	}
+	if expected := now.Add(time.Hour); !s.ExpiresAt().Equal(expected) {
+		t.Errorf("Session.ExpiresAt returned: %+v, expected: %+v", s.ExpiresAt(), expected)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat session file: %+v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Session file mode: %v, expected: 0600", info.Mode().Perm())
+	}
+
+	reloaded, err := Load(tgtg.NewClient(nil), path)
+	if err != nil {
+		t.Fatalf("Load returned error: %+v", err)
+	}
+	if reloaded.Client().RefreshToken != "new_refresh" || !reloaded.ExpiresAt().Equal(now.Add(time.Hour)) {
+		t.Errorf("Load returned: %+v, expected persisted refreshed session", reloaded.file)
+	}
+}
+
+func TestSession_RefreshRejected(t *testing.T) {
+	client, mux := setupUpstream(t)
+
+	var refreshes int32
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		var body tgtg.RefreshTokensRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		// Rotated refresh token must never be spent again.
+		if body.RefreshToken != "refresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&refreshes, 1)
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"access_token": "new_access", "refresh_token": "new_refresh"}`)
+	})
+
+	s, err := Load(client, filepath.Join(t.TempDir(), "session.json"))
+	if err != nil {
+		t.Fatalf("Load returned error: %+v", err)
+	}
+	if err := s.Set(client.WithAuth("access", "refresh", "1"), time.Hour); err != nil {
+		t.Fatalf("Session.Set returned error: %+v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.RefreshRejected(context.Background(), "access"); err != nil {
+				t.Errorf("Session.RefreshRejected returned error: %+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if actual := atomic.LoadInt32(&refreshes); actual != 1 {
+		t.Errorf("Refresh requests sent: %d, expected: 1", actual)
+	}
+	if actual := s.Client().AccessToken; actual != "new_access" {
+		t.Errorf("Session access token: %+v, expected: new_access", actual)
+	}
+}
+
+func TestSession_Login(t *testing.T) {
+	client, mux := setupUpstream(t)
+	mux.HandleFunc("/auth/v3/authByEmail", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"polling_id": "polling_id", "state": "WAIT"}`)
+	})
+	polls := 0
+	mux.HandleFunc("/auth/v3/authByRequestPollingId", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		fmt.Fprint(w, `{"access_token": "access", "refresh_token": "refresh", "startup_data": {"user": {"user_id": "1"}}}`)
+	})
+
+	s, err := Load(client, filepath.Join(t.TempDir(), "session.json"))
+	if err != nil {
+		t.Fatalf("Load returned error: %+v", err)
+	}
+	if s.Authenticated() {
+		t.Fatal("Load returned authenticated Session for missing file.")
+	}
+
+	if err := s.Login(context.Background(), "test@example.com", "ANDROID", time.Millisecond); err != nil {
+		t.Fatalf("Session.Login returned error: %+v", err)
+	}
+
+	if polls != 2 {
+		t.Errorf("Polled %d times, expected: 2", polls)
+	}
+	if actual := s.Client(); !s.Authenticated() || actual.AccessToken != "access" || actual.UserID != "1" {
+		t.Errorf("Session.Client returned: %+v, expected logged in client", actual)
+	}
+	if client.AccessToken != "" {
+		t.Errorf("Base client access token: %+v, expected it not to change", client.AccessToken)
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 )
 
-const itemsBasePath = "item/v7"
+const (
+	itemsBasePath = "item/v7"
+
+	defaultGetManyConcurrency = 4
+)
 
 // ItemsService is an interface for interfacing with the Items endpoints of the Too Good To Go API.
 type ItemsService interface {
@@ -22,29 +27,40 @@ type ItemsServiceOp struct {
 
 var _ ItemsService = &ItemsServiceOp{}
 
-// List handles listing all items.
+// List handles listing all items. Responses may be served from cache, see SetCache.
 func (s *ItemsServiceOp) List(ctx context.Context, listItemsRequest *ListItemsRequest) (*ListItemsResponse, *http.Response, error) {
 	if listItemsRequest == nil {
 		return nil, nil, NewArgumentError("listItemsRequest", "must not be nil")
 	}
 
-	if listItemsRequest.UserID == "" {
+	// copy request, so caller's value is not mutated.
+	request := *listItemsRequest
+	if request.UserID == "" {
 		if s.client.UserID == "" {
 			return nil, nil, NewArgumentError("listItemsRequest.UserID", "must not be nil - client has no user id set - please log in using Auth service first or provide UserID in listItemsRequest")
 		}
 		// if UserID not passed, but we have already authenticated with API, use user id stored in client.
-		listItemsRequest.UserID = s.client.UserID
+		request.UserID = s.client.UserID
 	}
 
 	url := fmt.Sprintf("%s/", itemsBasePath)
-	req, err := s.client.NewRequest(http.MethodPost, url, listItemsRequest)
+	req, err := s.client.NewRequest(http.MethodPost, url, &request)
 	if err != nil {
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
 
 	listItemsResponse := &ListItemsResponse{}
-	response, err := s.client.Do(ctx, req, listItemsResponse)
+	fetch := func() (*http.Response, error) {
+		return s.client.Do(withServiceMethod(ctx, "Items.List"), req, listItemsResponse)
+	}
+
+	var response *http.Response
+	if s.client.cache != nil && s.client.cache.ttls.ItemsList > 0 {
+		response, err = s.client.cache.load(ctx, s.client, req, itemsListCacheKey(&request), s.client.cache.ttls.ItemsList, listItemsResponse, fetch)
+	} else {
+		response, err = fetch()
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -52,7 +68,7 @@ func (s *ItemsServiceOp) List(ctx context.Context, listItemsRequest *ListItemsRe
 	return listItemsResponse, response, nil
 }
 
-// Get handles getting specific information about particular item entry.
+// Get handles getting specific information about particular item entry. Responses may be served from cache, see SetCache.
 func (s *ItemsServiceOp) Get(ctx context.Context, getItemRequest *GetItemRequest, itemID string) (*GetItemResponse, *http.Response, error) {
 	if itemID == "" {
 		return nil, nil, NewArgumentError("itemID", "must not be nil")
@@ -62,23 +78,34 @@ func (s *ItemsServiceOp) Get(ctx context.Context, getItemRequest *GetItemRequest
 		return nil, nil, NewArgumentError("getItemRequest", "must not be nil")
 	}
 
-	if getItemRequest.UserID == "" {
+	// copy request, so caller's value is not mutated.
+	request := *getItemRequest
+	if request.UserID == "" {
 		if s.client.UserID == "" {
 			return nil, nil, NewArgumentError("getItemRequest.UserID", "must not be nil - client has no user id set - please log in using Auth service first or provide UserID in getItemRequest")
 		}
 		// if UserID not passed, but we have already authenticated with API, use user id stored in client.
-		getItemRequest.UserID = s.client.UserID
+		request.UserID = s.client.UserID
 	}
 
 	url := fmt.Sprintf("%s/%s", itemsBasePath, itemID)
-	req, err := s.client.NewRequest(http.MethodPost, url, getItemRequest)
+	req, err := s.client.NewRequest(http.MethodPost, url, &request)
 	if err != nil {
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
 
 	getItemResp := &GetItemResponse{}
-	response, err := s.client.Do(ctx, req, getItemResp)
+	fetch := func() (*http.Response, error) {
+		return s.client.Do(withServiceMethod(ctx, "Items.Get"), req, getItemResp)
+	}
+
+	var response *http.Response
+	if s.client.cache != nil && s.client.cache.ttls.ItemsGet > 0 {
+		response, err = s.client.cache.load(ctx, s.client, req, itemsGetCacheKey(itemID, &request), s.client.cache.ttls.ItemsGet, getItemResp, fetch)
+	} else {
+		response, err = fetch()
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -103,10 +130,80 @@ func (s *ItemsServiceOp) Favorite(ctx context.Context, favoriteItemRequest *Favo
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
 
-	response, err := s.client.Do(ctx, req, nil)
+	response, err := s.client.Do(withServiceMethod(ctx, "Items.Favorite"), req, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.client.cache != nil {
+		s.client.cache.invalidateItem(itemID)
+	}
+
 	return response, nil
 }
+
+// GetMany gets specific information about multiple item entries concurrently, using Get of given ItemsService,
+// e.g. client.Items. Duplicated item IDs are fetched once. Failure of single item does not abort the others: successfully
+// fetched items are returned by item ID, together with ItemsError holding errors by item ID, if any.
+// Requests are subject to client's rate limiting, see SetRateLimiter.
+func GetMany(ctx context.Context, service ItemsService, itemIDs []string, opts *GetManyOptions) (map[string]*GetItemResponse, error) {
+	if opts == nil {
+		opts = &GetManyOptions{}
+	}
+
+	request := opts.Request
+	if request == nil {
+		request = &GetItemRequest{}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultGetManyConcurrency
+	}
+
+	seen := make(map[string]bool, len(itemIDs))
+	ids := make(chan string)
+	go func() {
+		defer close(ids)
+		for _, itemID := range itemIDs {
+			if !seen[itemID] {
+				seen[itemID] = true
+				ids <- itemID
+			}
+		}
+	}()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		items = make(map[string]*GetItemResponse, len(itemIDs))
+		errs  = make(map[string]error)
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for itemID := range ids {
+				var item *GetItemResponse
+				err := ctx.Err()
+				if err == nil {
+					item, _, err = service.Get(ctx, request, itemID)
+				}
+
+				mu.Lock()
+				if err != nil {
+					errs[itemID] = err
+				} else {
+					items[itemID] = item
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return items, &ItemsError{Errors: errs}
+	}
+	return items, nil
+}
@@ -0,0 +1,232 @@
+package tgtg
+
+import (
+	"fmt"
+	"time"
+)
+
+// ItemCategory represents a Too Good To Go item category, used in ListItemsRequest.ItemCategories.
+type ItemCategory string
+
+// Item categories known to Too Good To Go API.
+const (
+	ItemCategoryMeal       ItemCategory = "MEAL"
+	ItemCategoryBakedGoods ItemCategory = "BAKED_GOODS"
+	ItemCategoryGroceries  ItemCategory = "GROCERIES"
+	ItemCategoryOther      ItemCategory = "OTHER"
+)
+
+// DietCategory represents a Too Good To Go diet category, used in ListItemsRequest.DietCategories.
+type DietCategory string
+
+// Diet categories known to Too Good To Go API.
+const (
+	DietCategoryVegetarian DietCategory = "VEGETARIAN"
+	DietCategoryVegan      DietCategory = "VEGAN"
+)
+
+// Bounds of ListItemsRequest values accepted by Too Good To Go API.
+const (
+	MaxRadius   = 100
+	MaxPageSize = 400
+
+	defaultPageSize = 20
+)
+
+// Valid reports whether c is an item category known to Too Good To Go API.
+func (c ItemCategory) Valid() bool {
+	switch c {
+	case ItemCategoryMeal, ItemCategoryBakedGoods, ItemCategoryGroceries, ItemCategoryOther:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether c is a diet category known to Too Good To Go API.
+func (c DietCategory) Valid() bool {
+	switch c {
+	case DietCategoryVegetarian, DietCategoryVegan:
+		return true
+	}
+	return false
+}
+
+// Validate checks ListItemsRequest values against bounds and formats accepted by Too Good To Go API,
+// returning ArgumentError describing the first invalid one. Radius is in kilometers and has to be
+// within (0, MaxRadius], PageSize within [1, MaxPageSize], Page at least 1. PickupEarliest and
+// PickupLatest, if set, have to be RFC 3339 timestamps, earliest not after latest.
+func (r *ListItemsRequest) Validate() error {
+	if r.Radius <= 0 || r.Radius > MaxRadius {
+		return NewArgumentError("listItemsRequest.Radius", fmt.Sprintf("must be within (0, %d]", MaxRadius))
+	}
+
+	if r.PageSize < 1 || r.PageSize > MaxPageSize {
+		return NewArgumentError("listItemsRequest.PageSize", fmt.Sprintf("must be within [1, %d]", MaxPageSize))
+	}
+
+	if r.Page < 1 {
+		return NewArgumentError("listItemsRequest.Page", "must be at least 1")
+	}
+
+	if r.Origin == nil {
+		return NewArgumentError("listItemsRequest.Origin", "must not be nil")
+	}
+
+	if r.Origin.Latitude < -90 || r.Origin.Latitude > 90 || r.Origin.Longitude < -180 || r.Origin.Longitude > 180 {
+		return NewArgumentError("listItemsRequest.Origin", "must have latitude within [-90, 90] and longitude within [-180, 180]")
+	}
+
+	for _, category := range r.ItemCategories {
+		if !ItemCategory(category).Valid() {
+			return NewArgumentError("listItemsRequest.ItemCategories", fmt.Sprintf("unknown item category %q", category))
+		}
+	}
+
+	for _, category := range r.DietCategories {
+		if !DietCategory(category).Valid() {
+			return NewArgumentError("listItemsRequest.DietCategories", fmt.Sprintf("unknown diet category %q", category))
+		}
+	}
+
+	var earliest, latest time.Time
+	var err error
+	if r.PickupEarliest != "" {
+		earliest, err = time.Parse(time.RFC3339, r.PickupEarliest)
+		if err != nil {
+			return NewArgumentError("listItemsRequest.PickupEarliest", "must be RFC 3339 timestamp")
+		}
+	}
+	if r.PickupLatest != "" {
+		latest, err = time.Parse(time.RFC3339, r.PickupLatest)
+		if err != nil {
+			return NewArgumentError("listItemsRequest.PickupLatest", "must be RFC 3339 timestamp")
+		}
+	}
+	if !earliest.IsZero() && !latest.IsZero() && earliest.After(latest) {
+		return NewArgumentError("listItemsRequest.PickupEarliest", "must not be after PickupLatest")
+	}
+
+	return nil
+}
+
+// ListItemsQuery is a fluent builder of ListItemsRequest. Values are validated by Build,
+// before any request is sent.
+//
+//	request, err := tgtg.NewListItemsQuery(tgtg.Origin{Latitude: 52.2, Longitude: 21.0}, 5).
+//		ItemCategories(tgtg.ItemCategoryMeal).
+//		DietCategories(tgtg.DietCategoryVegan).
+//		WithStockOnly().
+//		Build()
+type ListItemsQuery struct {
+	request ListItemsRequest
+}
+
+// NewListItemsQuery returns a new ListItemsQuery searching within radius kilometers from origin,
+// returning first page of 20 items.
+func NewListItemsQuery(origin Origin, radius int) *ListItemsQuery {
+	return &ListItemsQuery{
+		request: ListItemsRequest{
+			Origin:   &origin,
+			Radius:   radius,
+			Page:     1,
+			PageSize: defaultPageSize,
+		},
+	}
+}
+
+// UserID sets user ID. If not set, user ID of the client is used.
+func (q *ListItemsQuery) UserID(userID string) *ListItemsQuery {
+	q.request.UserID = userID
+	return q
+}
+
+// Page sets page number, starting from 1, and page size.
+func (q *ListItemsQuery) Page(page, pageSize int) *ListItemsQuery {
+	q.request.Page = page
+	q.request.PageSize = pageSize
+	return q
+}
+
+// ItemCategories limits results to given item categories.
+func (q *ListItemsQuery) ItemCategories(categories ...ItemCategory) *ListItemsQuery {
+	for _, category := range categories {
+		q.request.ItemCategories = append(q.request.ItemCategories, string(category))
+	}
+	return q
+}
+
+// DietCategories limits results to given diet categories.
+func (q *ListItemsQuery) DietCategories(categories ...DietCategory) *ListItemsQuery {
+	for _, category := range categories {
+		q.request.DietCategories = append(q.request.DietCategories, string(category))
+	}
+	return q
+}
+
+// PickupBetween limits results to items with pickup window between earliest and latest.
+// Zero time leaves given bound unset.
+func (q *ListItemsQuery) PickupBetween(earliest, latest time.Time) *ListItemsQuery {
+	q.request.PickupEarliest = formatPickupTime(earliest)
+	q.request.PickupLatest = formatPickupTime(latest)
+	return q
+}
+
+// Search limits results to items matching search phrase.
+func (q *ListItemsQuery) Search(phrase string) *ListItemsQuery {
+	q.request.SearchPhrase = phrase
+	return q
+}
+
+// Discover enables discover mode.
+func (q *ListItemsQuery) Discover() *ListItemsQuery {
+	q.request.Discover = true
+	return q
+}
+
+// FavoritesOnly limits results to favorite items.
+func (q *ListItemsQuery) FavoritesOnly() *ListItemsQuery {
+	q.request.FavoritesOnly = true
+	return q
+}
+
+// WithStockOnly limits results to items currently available.
+func (q *ListItemsQuery) WithStockOnly() *ListItemsQuery {
+	q.request.WithStockOnly = true
+	return q
+}
+
+// HiddenOnly limits results to hidden items.
+func (q *ListItemsQuery) HiddenOnly() *ListItemsQuery {
+	q.request.HiddenOnly = true
+	return q
+}
+
+// WeCareOnly limits results to items of stores marked as We Care.
+func (q *ListItemsQuery) WeCareOnly() *ListItemsQuery {
+	q.request.WeCareOnly = true
+	return q
+}
+
+// Build validates the query and returns ListItemsRequest, or ArgumentError if any value is invalid.
+func (q *ListItemsQuery) Build() (*ListItemsRequest, error) {
+	request := q.request
+	request.ItemCategories = append([]string(nil), q.request.ItemCategories...)
+	request.DietCategories = append([]string(nil), q.request.DietCategories...)
+	if q.request.Origin != nil {
+		origin := *q.request.Origin
+		request.Origin = &origin
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// formatPickupTime formats pickup time bound, returning empty string for zero time.
+func formatPickupTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
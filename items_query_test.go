@@ -0,0 +1,92 @@
+package tgtg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListItemsQuery_Build(t *testing.T) {
+	earliest := time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)
+	latest := earliest.Add(2 * time.Hour)
+
+	actual, err := NewListItemsQuery(Origin{Latitude: 45.624, Longitude: 9.282}, 10).
+		UserID("1").
+		Page(2, 50).
+		ItemCategories(ItemCategoryMeal, ItemCategoryBakedGoods).
+		DietCategories(DietCategoryVegan).
+		PickupBetween(earliest, latest).
+		Search("pizza").
+		WithStockOnly().
+		FavoritesOnly().
+		Build()
+	if err != nil {
+		t.Fatalf("ListItemsQuery.Build returned error: %+v", err)
+	}
+
+	expected := &ListItemsRequest{
+		PageSize:       50,
+		Page:           2,
+		UserID:         "1",
+		Radius:         10,
+		Origin:         &Origin{Latitude: 45.624, Longitude: 9.282},
+		ItemCategories: []string{"MEAL", "BAKED_GOODS"},
+		DietCategories: []string{"VEGAN"},
+		PickupEarliest: "2021-12-01T18:00:00Z",
+		PickupLatest:   "2021-12-01T20:00:00Z",
+		SearchPhrase:   "pizza",
+		FavoritesOnly:  true,
+		WithStockOnly:  true,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ListItemsQuery.Build returned: %+v, expected: %+v", actual, expected)
+	}
+}
+
+func TestListItemsQuery_BuildArgumentError(t *testing.T) {
+	origin := Origin{Latitude: 45.624, Longitude: 9.282}
+	now := time.Now()
+
+	testCases := []struct {
+		title    string
+		query    *ListItemsQuery
+		argument string
+	}{
+		{title: "zero radius", query: NewListItemsQuery(origin, 0), argument: "listItemsRequest.Radius"},
+		{title: "radius too big", query: NewListItemsQuery(origin, MaxRadius+1), argument: "listItemsRequest.Radius"},
+		{title: "page size too big", query: NewListItemsQuery(origin, 1).Page(1, MaxPageSize+1), argument: "listItemsRequest.PageSize"},
+		{title: "zero page", query: NewListItemsQuery(origin, 1).Page(0, 10), argument: "listItemsRequest.Page"},
+		{title: "invalid origin", query: NewListItemsQuery(Origin{Latitude: 91}, 1), argument: "listItemsRequest.Origin"},
+		{title: "unknown item category", query: NewListItemsQuery(origin, 1).ItemCategories("SHOES"), argument: "listItemsRequest.ItemCategories"},
+		{title: "unknown diet category", query: NewListItemsQuery(origin, 1).DietCategories("CARNIVORE"), argument: "listItemsRequest.DietCategories"},
+		{title: "reversed pickup window", query: NewListItemsQuery(origin, 1).PickupBetween(now, now.Add(-time.Hour)), argument: "listItemsRequest.PickupEarliest"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := tc.query.Build()
+			if _, ok := err.(*ArgumentError); !ok {
+				t.Fatalf("ListItemsQuery.Build returned: %+v, expected: ArgumentError", err)
+			}
+			if !strings.HasPrefix(err.Error(), tc.argument+" ") {
+				t.Errorf("Error: %+v did not refer to %+v", err, tc.argument)
+			}
+		})
+	}
+}
+
+func TestListItemsRequest_Validate(t *testing.T) {
+	request := &ListItemsRequest{
+		Radius:         1,
+		PageSize:       1,
+		Page:           1,
+		Origin:         &Origin{},
+		PickupEarliest: "18:00",
+	}
+
+	err := request.Validate()
+	if err == nil || !strings.Contains(err.Error(), "listItemsRequest.PickupEarliest") {
+		t.Errorf("Error: %+v did not contain listItemsRequest.PickupEarliest", err)
+	}
+}
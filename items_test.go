@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -70,8 +72,11 @@ func TestItemsService_List(t *testing.T) {
 		}
 
 		testMethod(t, r, http.MethodPost)
-		if !reflect.DeepEqual(req, listRequest) {
-			t.Errorf("Request body: %+v, expected: %+v", req, listRequest)
+		// UserID is taken from the client, without mutating caller's request.
+		expectedRequest := *listRequest
+		expectedRequest.UserID = "1"
+		if !reflect.DeepEqual(req, &expectedRequest) {
+			t.Errorf("Request body: %+v, expected: %+v", req, &expectedRequest)
 		}
 
 		fmt.Fprintf(w, `
@@ -107,6 +112,10 @@ func TestItemsService_List(t *testing.T) {
 		t.Errorf("Items.List returned error: %+v", err)
 	}
 
+	if listRequest.UserID != "" {
+		t.Errorf("Items.List mutated request UserID: %+v, expected empty string", listRequest.UserID)
+	}
+
 	expected := &ListItemsResponse{
 		Items: []Items{
 			{
@@ -231,8 +240,11 @@ func TestItemsService_Get(t *testing.T) {
 		}
 
 		testMethod(t, r, http.MethodPost)
-		if !reflect.DeepEqual(req, getRequest) {
-			t.Errorf("Request body: %+v, expected: %+v", req, getRequest)
+		// UserID is taken from the client, without mutating caller's request.
+		expectedRequest := *getRequest
+		expectedRequest.UserID = "1"
+		if !reflect.DeepEqual(req, &expectedRequest) {
+			t.Errorf("Request body: %+v, expected: %+v", req, &expectedRequest)
 		}
 
 		fmt.Fprintf(w, `
@@ -337,3 +349,85 @@ func TestItemsService_FavoriteArgumentError(t *testing.T) {
 		t.Errorf("Error: %+v did not contain itemID", err)
 	}
 }
+
+func TestGetMany(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.SetAuthContext("access_token", "refresh_token", "1")
+
+	var (
+		mu       sync.Mutex
+		calls    = map[string]int{}
+		inFlight int
+		maxSeen  int
+	)
+	mux.HandleFunc("/item/v7/", func(w http.ResponseWriter, r *http.Request) {
+		itemID := strings.TrimPrefix(r.URL.Path, "/item/v7/")
+
+		mu.Lock()
+		calls[itemID]++
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if itemID == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"item": {"item_id": "%s"}}`, itemID)
+	})
+
+	ids := []string{"1", "2", "3", "missing", "2", "4", "5", "1"}
+	actual, err := GetMany(context.Background(), client.Items, ids, &GetManyOptions{Concurrency: 2})
+
+	itemsErr, ok := err.(*ItemsError)
+	if !ok {
+		t.Fatalf("GetMany returned: %+v, expected: ItemsError", err)
+	}
+	if _, ok := itemsErr.Errors["missing"]; !ok || len(itemsErr.Errors) != 1 {
+		t.Errorf("ItemsError: %+v, expected error of missing item only", itemsErr.Errors)
+	}
+
+	if len(actual) != 5 {
+		t.Errorf("GetMany returned %d items, expected: 5", len(actual))
+	}
+	for _, itemID := range []string{"1", "2", "3", "4", "5"} {
+		if item, ok := actual[itemID]; !ok || item.Item.ItemID != itemID {
+			t.Errorf("GetMany returned: %+v for %s", item, itemID)
+		}
+		if calls[itemID] != 1 {
+			t.Errorf("Item %s fetched %d times, expected once", itemID, calls[itemID])
+		}
+	}
+
+	if maxSeen > 2 {
+		t.Errorf("Concurrent requests: %d, expected at most 2", maxSeen)
+	}
+}
+
+func TestGetManyCancelled(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	actual, err := GetMany(ctx, client.Items, []string{"1", "2"}, nil)
+	if len(actual) != 0 {
+		t.Errorf("GetMany returned: %+v, expected no items", actual)
+	}
+
+	itemsErr, ok := err.(*ItemsError)
+	if !ok || itemsErr.Errors["1"] != context.Canceled || itemsErr.Errors["2"] != context.Canceled {
+		t.Errorf("GetMany returned: %+v, expected context.Canceled for every item", err)
+	}
+}
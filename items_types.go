@@ -1,7 +1,5 @@
 package tgtg
 
-import "time"
-
 // GetItemRequest represents a request body to get item details.
 type GetItemRequest struct {
 	UserID string  `json:"user_id"`
@@ -21,11 +19,21 @@ type GetItemResponse struct {
 	NewItem        bool           `json:"new_item"`
 	PickupInterval PickupInterval `json:"pickup_interval"`
 	PickupLocation PickupLocation `json:"pickup_location"`
-	PurchaseEnd    string         `json:"purchase_end"`
+	PurchaseEnd    Timestamp      `json:"purchase_end"`
 	SharingURL     string         `json:"sharing_url"`
 }
 
+// GetManyOptions specifies options of getting multiple items at once.
+type GetManyOptions struct {
+	// Request used for every item, empty one if nil.
+	Request *GetItemRequest
+
+	// Concurrency limits number of concurrent requests, 4 if not positive.
+	Concurrency int
+}
+
 // ListItemsRequest represents a request body to list all items.
+// Use ListItemsQuery to build validated request.
 type ListItemsRequest struct {
 	PageSize int `json:"page_size"`
 	Page     int `json:"page"`
@@ -35,11 +43,14 @@ type ListItemsRequest struct {
 	Radius int     `json:"radius"`
 	Origin *Origin `json:"origin"`
 
+	// ItemCategories and DietCategories take ItemCategory and DietCategory values.
 	ItemCategories []string `json:"item_categories"`
 	DietCategories []string `json:"diet_categories"`
-	PickupEarliest string   `json:"pickup_earliest"`
-	PickupLatest   string   `json:"pickup_latest"`
-	SearchPhrase   string   `json:"search_phrase"`
+
+	// PickupEarliest and PickupLatest take RFC 3339 timestamps.
+	PickupEarliest string `json:"pickup_earliest"`
+	PickupLatest   string `json:"pickup_latest"`
+	SearchPhrase   string `json:"search_phrase"`
 
 	Discover      bool `json:"discover"`
 	FavoritesOnly bool `json:"favorites_only"`
@@ -94,9 +105,9 @@ type Items struct {
 	DisplayName    string         `json:"display_name"`
 	PickupInterval PickupInterval `json:"pickup_interval"`
 	PickupLocation Location       `json:"pickup_location"`
-	PurchaseEnd    time.Time      `json:"purchase_end"`
+	PurchaseEnd    Timestamp      `json:"purchase_end"`
 	ItemsAvailable int            `json:"items_available"`
-	SoldOutAt      time.Time      `json:"sold_out_at"`
+	SoldOutAt      Timestamp      `json:"sold_out_at"`
 	Distance       float64        `json:"distance"`
 	Favorite       bool           `json:"favorite"`
 	InSalesWindow  bool           `json:"in_sales_window"`
@@ -134,8 +145,8 @@ type Price struct {
 
 // PickupInterval represents a Too Good To Go Pickup Interval details.
 type PickupInterval struct {
-	End   time.Time `json:"end"`
-	Start time.Time `json:"start"`
+	End   Timestamp `json:"end"`
+	Start Timestamp `json:"start"`
 }
 
 // PickupLocation represents a Too Good To Go Pickup Location details.
@@ -192,7 +203,7 @@ type StoreItems struct {
 	NewItem        bool           `json:"new_item"`
 	PickupInterval PickupInterval `json:"pickup_interval"`
 	PickupLocation PickupLocation `json:"pickup_location"`
-	PurchaseEnd    string         `json:"purchase_end"`
+	PurchaseEnd    Timestamp      `json:"purchase_end"`
 }
 
 // Milestones represents a Too Good To Go Milestones details.
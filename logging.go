@@ -0,0 +1,107 @@
+package tgtg
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Logger is a minimal structured logger interface. Arguments are alternating keys and values.
+// *slog.Logger from log/slog satisfies it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// SetLogger is a ClientOption enabling structured logging of every request sent by the client.
+// Successful requests are logged at debug level, token refreshes at info level, 4xx responses at warn level,
+// 5xx responses and transport errors at error level. Tokens, emails and request bodies are never logged.
+func SetLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return NewArgumentError("logger", "must not be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// logging is a Middleware logging requests with client's Logger.
+func (c *Client) logging(next Handler) Handler {
+	return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		response, err := next(ctx, method, req)
+		latency := time.Since(start)
+
+		path := Endpoint(ctx)
+		args := []interface{}{
+			"group", endpointGroup(method, path),
+			"method", method,
+			"http_method", req.Method,
+			"path", path,
+			"latency", latency,
+			"retries", Attempts(ctx) - 1,
+			"token_refresh", method == "Auth.Refresh",
+		}
+
+		status := 0
+		if response != nil {
+			status = response.StatusCode
+			args = append(args, "status", status)
+		}
+		if errorResponse, ok := err.(*ErrorResponse); ok {
+			args = append(args, "errors", errorResponse.Errors)
+		} else if err != nil {
+			args = append(args, "error", err.Error())
+		}
+
+		switch {
+		case err != nil && (status == 0 || status >= 500):
+			c.logger.Error("tgtg request failed", args...)
+		case err != nil:
+			c.logger.Warn("tgtg request rejected", args...)
+		case method == "Auth.Refresh":
+			c.logger.Info("tgtg tokens refreshed", args...)
+		default:
+			c.logger.Debug("tgtg request", args...)
+		}
+
+		return response, err
+	}
+}
+
+// endpointGroup returns group of the endpoint, e.g. "Items", based on service method name,
+// or first segment of the path for requests not sent by services.
+func endpointGroup(method, path string) string {
+	if i := strings.Index(method, "."); i > 0 {
+		return method[:i]
+	}
+	return strings.SplitN(path, "/", 2)[0]
+}
+
+type attemptsKey struct{}
+
+// withAttempts returns a copy of ctx carrying a counter of attempts of sending single request.
+func withAttempts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, new(int32))
+}
+
+// addAttempt increments counter of attempts carried by ctx, if any.
+func addAttempt(ctx context.Context) {
+	if counter, ok := ctx.Value(attemptsKey{}).(*int32); ok {
+		atomic.AddInt32(counter, 1)
+	}
+}
+
+// Attempts returns number of attempts of sending request so far, for ctx passed to Middleware.
+// Middleware retrying requests by calling next Handler multiple times increases it.
+func Attempts(ctx context.Context) int {
+	if counter, ok := ctx.Value(attemptsKey{}).(*int32); ok {
+		return int(atomic.LoadInt32(counter))
+	}
+	return 0
+}
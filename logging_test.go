@@ -0,0 +1,123 @@
+package tgtg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	entry := logEntry{level: level, msg: msg, args: map[string]interface{}{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log("debug", msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.log("info", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log("warn", msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log("error", msg, args) }
+
+func TestSetLogger(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	logger := &testLogger{}
+	retry := func(next Handler) Handler {
+		return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+			response, err := next(ctx, method, req)
+			if err != nil && response != nil && response.StatusCode == http.StatusServiceUnavailable {
+				return next(ctx, method, req)
+			}
+			return response, err
+		}
+	}
+	if err := SetLogger(logger)(client); err != nil {
+		t.Fatalf("SetLogger returned error: %+v", err)
+	}
+	if err := SetMiddleware(retry)(client); err != nil {
+		t.Fatalf("SetMiddleware returned error: %+v", err)
+	}
+	client.SetAuthContext("secret-access", "secret-refresh", "1")
+
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "secret-new-access"}`)
+	})
+	unavailable := true
+	mux.HandleFunc("/item/v7/1", func(w http.ResponseWriter, r *http.Request) {
+		if unavailable {
+			unavailable = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors": [{"code": "NOT_FOUND"}]}`)
+	})
+
+	_, _, err := client.Auth.Refresh(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Auth.Refresh returned error: %+v", err)
+	}
+
+	_, _, err = client.Items.Get(context.Background(), &GetItemRequest{}, "1")
+	if err == nil {
+		t.Fatal("Items.Get returned no error.")
+	}
+
+	if len(logger.entries) != 3 {
+		t.Fatalf("Logged entries: %+v, expected 3", logger.entries)
+	}
+
+	testCases := []struct {
+		level   string
+		group   string
+		method  string
+		status  int
+		retries int
+		refresh bool
+	}{
+		{level: "info", group: "Auth", method: "Auth.Refresh", status: 200, retries: 0, refresh: true},
+		{level: "error", group: "Items", method: "Items.Get", status: 503, retries: 0},
+		{level: "warn", group: "Items", method: "Items.Get", status: 404, retries: 1},
+	}
+	for i, tc := range testCases {
+		entry := logger.entries[i]
+		if entry.level != tc.level {
+			t.Errorf("Entry %d level: %+v, expected: %+v", i, entry.level, tc.level)
+		}
+		if entry.args["group"] != tc.group || entry.args["method"] != tc.method {
+			t.Errorf("Entry %d: %+v, expected group %+v and method %+v", i, entry.args, tc.group, tc.method)
+		}
+		if entry.args["status"] != tc.status || entry.args["retries"] != tc.retries || entry.args["token_refresh"] != tc.refresh {
+			t.Errorf("Entry %d: %+v, expected status %+v, retries %+v, token_refresh %+v", i, entry.args, tc.status, tc.retries, tc.refresh)
+		}
+		if _, ok := entry.args["latency"]; !ok {
+			t.Errorf("Entry %d: %+v, expected latency", i, entry.args)
+		}
+		if strings.Contains(fmt.Sprint(entry.args), "secret") {
+			t.Errorf("Entry %d: %+v, contains secrets", i, entry.args)
+		}
+	}
+}
+
+func TestEndpointGroup(t *testing.T) {
+	if actual := endpointGroup("Orders.Active", "order/v6/active"); actual != "Orders" {
+		t.Errorf("endpointGroup returned: %+v, expected: Orders", actual)
+	}
+	if actual := endpointGroup("", "order/v6/active"); actual != "order" {
+		t.Errorf("endpointGroup returned: %+v, expected: order", actual)
+	}
+}
@@ -0,0 +1,247 @@
+// Package metrics instruments Too Good To Go API client and exposes collected metrics
+// in Prometheus text exposition format, with no dependency on Prometheus client libraries.
+//
+// Requests are instrumented by adding Middleware to the client:
+//
+//	m := metrics.New()
+//	client, err := tgtg.New(nil, tgtg.SetMiddleware(m.Middleware()))
+//	http.Handle("/metrics", m)
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are request latency histogram buckets, in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects Too Good To Go API client metrics. It is safe for concurrent use.
+// Metrics implements http.Handler serving collected metrics in Prometheus text format.
+type Metrics struct {
+	mu sync.Mutex
+
+	buckets []float64
+
+	// requests counts requests by service method and status.
+	requests map[[2]string]float64
+
+	// latencies holds request latency histograms by service method.
+	latencies map[string]*histogram
+
+	// rateLimited counts 429 TOO_MANY_REQUESTS responses by service method.
+	rateLimited map[string]float64
+
+	// tokenRefreshes counts successful token refreshes.
+	tokenRefreshes float64
+
+	// available holds available bags by item ID and display name.
+	available map[[2]string]float64
+}
+
+type histogram struct {
+	counts []float64
+	sum    float64
+	count  float64
+}
+
+var _ http.Handler = &Metrics{}
+
+// New returns Metrics with DefaultBuckets used for latency histograms.
+func New() *Metrics {
+	return NewWithBuckets(DefaultBuckets)
+}
+
+// NewWithBuckets returns Metrics with given latency histogram buckets, in seconds.
+func NewWithBuckets(buckets []float64) *Metrics {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &Metrics{
+		buckets:     sorted,
+		requests:    map[[2]string]float64{},
+		latencies:   map[string]*histogram{},
+		rateLimited: map[string]float64{},
+		available:   map[[2]string]float64{},
+	}
+}
+
+// Middleware returns tgtg.Middleware counting requests by service method and status, measuring their latency,
+// and counting rate limited requests and token refreshes. Requests sent directly with Client.Do
+// are reported under "Client.Do" method.
+func (m *Metrics) Middleware() tgtg.Middleware {
+	return func(next tgtg.Handler) tgtg.Handler {
+		return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			response, err := next(ctx, method, req)
+			m.observeRequest(method, response, err, time.Since(start))
+			return response, err
+		}
+	}
+}
+
+// ObserveItems sets available bags gauge of every item in items, e.g. on every watcher poll.
+func (m *Metrics) ObserveItems(items []tgtg.Items) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, item := range items {
+		m.available[[2]string{item.Item.ItemID, item.DisplayName}] = float64(item.ItemsAvailable)
+	}
+}
+
+// SetAvailable sets available bags gauge of single item.
+func (m *Metrics) SetAvailable(itemID, displayName string, available int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.available[[2]string{itemID, displayName}] = float64(available)
+}
+
+// ServeHTTP implements http.Handler interface's method.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	m.WriteTo(w)
+}
+
+// WriteTo writes collected metrics in Prometheus text format to w.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	header(&b, "tgtg_requests_total", "counter", "Too Good To Go API requests by service method and status.")
+	for _, key := range pairKeys(m.requests) {
+		sample(&b, "tgtg_requests_total", labels("method", key[0], "status", key[1]), m.requests[key])
+	}
+
+	header(&b, "tgtg_request_duration_seconds", "histogram", "Too Good To Go API request latency by service method.")
+	for _, method := range histogramKeys(m.latencies) {
+		h := m.latencies[method]
+		for i, bucket := range m.buckets {
+			le := strconv.FormatFloat(bucket, 'g', -1, 64)
+			sample(&b, "tgtg_request_duration_seconds_bucket", labels("method", method, "le", le), h.counts[i])
+		}
+		sample(&b, "tgtg_request_duration_seconds_bucket", labels("method", method, "le", "+Inf"), h.count)
+		sample(&b, "tgtg_request_duration_seconds_sum", labels("method", method), h.sum)
+		sample(&b, "tgtg_request_duration_seconds_count", labels("method", method), h.count)
+	}
+
+	header(&b, "tgtg_rate_limited_total", "counter", "Too Good To Go API requests rejected with 429 TOO_MANY_REQUESTS by service method.")
+	for _, method := range counterKeys(m.rateLimited) {
+		sample(&b, "tgtg_rate_limited_total", labels("method", method), m.rateLimited[method])
+	}
+
+	header(&b, "tgtg_token_refreshes_total", "counter", "Successful Too Good To Go token refreshes.")
+	sample(&b, "tgtg_token_refreshes_total", "", m.tokenRefreshes)
+
+	header(&b, "tgtg_item_available_bags", "gauge", "Bags available per observed item.")
+	for _, key := range pairKeys(m.available) {
+		sample(&b, "tgtg_item_available_bags", labels("item_id", key[0], "display_name", key[1]), m.available[key])
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// observeRequest records single request.
+func (m *Metrics) observeRequest(method string, response *http.Response, err error, latency time.Duration) {
+	if method == "" {
+		method = "Client.Do"
+	}
+
+	status := "error"
+	if response != nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[[2]string{method, status}]++
+
+	h, ok := m.latencies[method]
+	if !ok {
+		h = &histogram{counts: make([]float64, len(m.buckets))}
+		m.latencies[method] = h
+	}
+	seconds := latency.Seconds()
+	for i, bucket := range m.buckets {
+		if seconds <= bucket {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		m.rateLimited[method]++
+	}
+
+	if method == "Auth.Refresh" && err == nil {
+		m.tokenRefreshes++
+	}
+}
+
+func header(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(b *strings.Builder, name, labels string, value float64) {
+	fmt.Fprintf(b, "%s%s %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// labels formats label pairs, escaping values as required by Prometheus text format.
+func labels(pairs ...string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], replacer.Replace(pairs[i+1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func histogramKeys(m map[string]*histogram) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func counterKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func pairKeys(m map[[2]string]float64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
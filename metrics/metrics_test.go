@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func TestMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	m := NewWithBuckets([]float64{60, 0.000001})
+	client, err := tgtg.New(nil, tgtg.SetMiddleware(m.Middleware()))
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.SetAuthContext("access_token", "refresh_token", "1")
+
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "access_token"}`)
+	})
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, _, err = client.Auth.Refresh(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Auth.Refresh returned error: %+v", err)
+	}
+	for i := 0; i < 2; i++ {
+		_, _, _ = client.Orders.Active(context.Background(), nil)
+	}
+
+	m.ObserveItems([]tgtg.Items{
+		{Item: tgtg.Item{ItemID: "1"}, DisplayName: `Bakery "Best"`, ItemsAvailable: 3},
+	})
+	m.SetAvailable("2", "Shop", 0)
+
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if actual := recorder.Header().Get("Content-Type"); actual != contentType {
+		t.Errorf("Content-Type: %+v, expected: %+v", actual, contentType)
+	}
+
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		"# TYPE tgtg_requests_total counter\n",
+		`tgtg_requests_total{method="Auth.Refresh",status="200"} 1`,
+		`tgtg_requests_total{method="Orders.Active",status="429"} 2`,
+		`tgtg_request_duration_seconds_bucket{method="Orders.Active",le="1e-06"} 0`,
+		`tgtg_request_duration_seconds_bucket{method="Orders.Active",le="60"} 2`,
+		`tgtg_request_duration_seconds_bucket{method="Orders.Active",le="+Inf"} 2`,
+		`tgtg_request_duration_seconds_count{method="Orders.Active"} 2`,
+		`tgtg_rate_limited_total{method="Orders.Active"} 2`,
+		"tgtg_token_refreshes_total 1",
+		`tgtg_item_available_bags{item_id="1",display_name="Bakery \"Best\""} 3`,
+		`tgtg_item_available_bags{item_id="2",display_name="Shop"} 0`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Metrics: %+v, did not contain %+v", body, expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tgtg
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Handler sends Too Good To Go API request on behalf of service method (e.g. "Items.List") and returns
+// its response. Method is empty for requests sent directly with Client.Do. Non 2xx responses are
+// returned together with ErrorResponse error.
+type Handler func(ctx context.Context, method string, req *http.Request) (*http.Response, error)
+
+// Middleware wraps Handler, allowing to inspect or mutate requests, responses and errors,
+// e.g. for logging, metrics or request signing.
+type Middleware func(next Handler) Handler
+
+// SetMiddleware is a ClientOption for adding middleware wrapping every request sent by the client.
+// Middleware is applied in order, first one being the outermost.
+func SetMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) error {
+		for _, m := range middleware {
+			if m == nil {
+				return NewArgumentError("middleware", "must not be nil")
+			}
+		}
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	}
+}
+
+type serviceMethodKey struct{}
+
+// withServiceMethod returns a copy of ctx carrying service method name.
+func withServiceMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, serviceMethodKey{}, method)
+}
+
+// ServiceMethod returns service method name (e.g. "Orders.Active") carried by ctx passed to Middleware,
+// or empty string if there is none.
+func ServiceMethod(ctx context.Context) string {
+	method, _ := ctx.Value(serviceMethodKey{}).(string)
+	return method
+}
+
+type endpointKey struct{}
+
+// withEndpoint returns a copy of ctx carrying request endpoint.
+func withEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// Endpoint returns endpoint of the request relative to client's BaseURL (e.g. "item/v7/123"),
+// for ctx passed to Middleware, or empty string if there is none.
+func Endpoint(ctx context.Context) string {
+	endpoint, _ := ctx.Value(endpointKey{}).(string)
+	return endpoint
+}
+
+// endpoint returns path of req relative to client's BaseURL.
+func (c *Client) endpoint(req *http.Request) string {
+	return strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, c.BaseURL.Path), "/")
+}
+
+// handler returns client's Handler wrapped with all middleware. Built-in middleware,
+// such as logging and rate limiting, is the innermost, so it observes every attempt as actually sent.
+func (c *Client) handler() Handler {
+	handler := c.send
+	if c.logger != nil {
+		handler = c.logging(handler)
+	}
+	if c.limiter != nil {
+		handler = c.rateLimiting(handler)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,88 @@
+package tgtg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSetMiddleware(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var calls []string
+	recorder := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+				calls = append(calls, fmt.Sprintf("%s before %s", name, method))
+				response, err := next(ctx, method, req)
+				calls = append(calls, fmt.Sprintf("%s after %d %v", name, response.StatusCode, err != nil))
+				return response, err
+			}
+		}
+	}
+	signer := func(next Handler) Handler {
+		return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Signature", "signed")
+			return next(ctx, method, req)
+		}
+	}
+
+	if err := SetMiddleware(recorder("outer"), recorder("inner"), signer)(client); err != nil {
+		t.Fatalf("SetMiddleware returned error: %+v", err)
+	}
+
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		if actual := r.Header.Get("X-Signature"); actual != "signed" {
+			t.Errorf("Request header: %+v, expected: signed", actual)
+		}
+		fmt.Fprint(w, `{"orders": []}`)
+	})
+	mux.HandleFunc("/order/v6/inactive", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, _, err := client.Orders.Active(context.Background(), &ActiveOrdersRequest{UserID: "1"})
+	if err != nil {
+		t.Fatalf("Orders.Active returned error: %+v", err)
+	}
+
+	_, _, err = client.Orders.Inactive(context.Background(), &InactiveOrdersRequest{UserID: "1"})
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Fatalf("Orders.Inactive returned: %+v, expected: ErrorResponse", err)
+	}
+
+	expected := []string{
+		"outer before Orders.Active",
+		"inner before Orders.Active",
+		"inner after 200 false",
+		"outer after 200 false",
+		"outer before Orders.Inactive",
+		"inner before Orders.Inactive",
+		"inner after 403 true",
+		"outer after 403 true",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Middleware calls: %+v, expected: %+v", calls, expected)
+	}
+}
+
+func TestSetMiddleware_ArgumentError(t *testing.T) {
+	_, err := New(nil, SetMiddleware(nil))
+	if err == nil {
+		t.Fatal("New returned no error.")
+	}
+}
+
+func TestServiceMethod(t *testing.T) {
+	if actual := ServiceMethod(context.Background()); actual != "" {
+		t.Errorf("ServiceMethod returned: %+v, expected empty string", actual)
+	}
+
+	ctx := withServiceMethod(context.Background(), "Items.Get")
+	if actual := ServiceMethod(ctx); actual != "Items.Get" {
+		t.Errorf("ServiceMethod returned: %+v, expected: Items.Get", actual)
+	}
+}
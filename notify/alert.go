@@ -0,0 +1,103 @@
+// Package notify renders alerts about Too Good To Go bags with user supplied templates, suppresses
+// repeated alerts, batches bursts of alerts into digests and fans messages out to pluggable sinks.
+//
+//	tmpl, err := notify.NewTemplate("alert", `{{.StoreName}}: {{.Available}} left for {{price .Price}}, pickup {{pickup .}}`)
+//	...
+//	notifier, err := notify.New(notify.Options{
+//		Template: tmpl,
+//		Cooldown: time.Hour,
+//		Sinks:    []notify.Sink{notify.WriterSink(os.Stdout)},
+//	})
+//	...
+//	err = notifier.Notify(ctx, notify.FromItems(items, time.Now()))
+package notify
+
+import (
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// State describes availability of an item.
+type State string
+
+// Item states. Alerts are deduplicated by item and state.
+const (
+	// StateAvailable items have bags available.
+	StateAvailable State = "available"
+
+	// StateSoldOut items have no bags available.
+	StateSoldOut State = "sold_out"
+)
+
+// Alert represents availability of a single item, as seen in Items.List or Items.Get response.
+type Alert struct {
+	Time time.Time
+
+	ItemID      string
+	StoreID     string
+	StoreName   string
+	DisplayName string
+	Available   int
+
+	// Price is price of a bag, Value its original value.
+	Price tgtg.Price
+	Value tgtg.Price
+
+	Pickup   tgtg.PickupInterval
+	TimeZone string
+
+	// Distance from search origin in kilometers, zero if unknown.
+	Distance float64
+
+	// SharingURL is link to the item in Too Good To Go app, set only in Items.Get responses.
+	SharingURL string
+}
+
+// FromItems returns Alert about items entry returned by Items.List, observed at given time.
+func FromItems(items tgtg.Items, at time.Time) Alert {
+	return Alert{
+		Time:        at,
+		ItemID:      items.Item.ItemID,
+		StoreID:     items.Store.StoreID,
+		StoreName:   items.Store.StoreName,
+		DisplayName: items.DisplayName,
+		Available:   items.ItemsAvailable,
+		Price:       items.Item.PriceIncludingTaxes,
+		Value:       items.Item.ValueIncludingTaxes,
+		Pickup:      items.PickupInterval,
+		TimeZone:    items.Store.StoreTimeZone,
+		Distance:    items.Distance,
+	}
+}
+
+// FromItemResponse returns Alert about item returned by Items.Get, observed at given time.
+func FromItemResponse(response *tgtg.GetItemResponse, at time.Time) Alert {
+	return Alert{
+		Time:        at,
+		ItemID:      response.Item.ItemID,
+		StoreID:     response.Store.StoreID,
+		StoreName:   response.Store.StoreName,
+		DisplayName: response.DisplayName,
+		Available:   response.ItemsAvailable,
+		Price:       response.Item.PriceIncludingTaxes,
+		Value:       response.Item.ValueIncludingTaxes,
+		Pickup:      response.PickupInterval,
+		TimeZone:    response.Store.StoreTimeZone,
+		Distance:    response.Distance,
+		SharingURL:  response.SharingURL,
+	}
+}
+
+// State returns availability state of the item.
+func (a Alert) State() State {
+	if a.Available > 0 {
+		return StateAvailable
+	}
+	return StateSoldOut
+}
+
+// PickupWindow returns pickup window in store's time zone.
+func (a Alert) PickupWindow() (tgtg.PickupWindow, error) {
+	return tgtg.NewPickupWindow(a.Pickup, a.TimeZone, nil)
+}
@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Message is a rendered notification.
+type Message struct {
+	// Text is rendered with alert template for single alert, or with digest template for batched alerts.
+	Text string
+
+	// Alerts lists alerts the message was rendered from.
+	Alerts []Alert
+}
+
+// Sink delivers messages, e.g. to chat, email or webhook.
+type Sink interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SinkFunc is an adapter allowing use of ordinary functions as Sink.
+type SinkFunc func(ctx context.Context, message Message) error
+
+// Send calls f(ctx, message).
+func (f SinkFunc) Send(ctx context.Context, message Message) error {
+	return f(ctx, message)
+}
+
+// WriterSink returns Sink writing message text, followed by a newline, to w.
+func WriterSink(w io.Writer) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(ctx context.Context, message Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := io.WriteString(w, message.Text+"\n")
+		return err
+	})
+}
+
+// SendError is returned when some sinks failed to deliver a message.
+type SendError struct {
+	Errors []error
+}
+
+var _ error = &SendError{}
+
+// Error implements error interface's method.
+func (e *SendError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("notify: %d sinks failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Options specifies options of Notifier.
+type Options struct {
+	// Template renders single alert, DefaultTemplate if nil.
+	Template *template.Template
+
+	// DigestTemplate renders batched alerts, DefaultDigestTemplate if nil.
+	DigestTemplate *template.Template
+
+	// Cooldown suppresses alerts about item in the same state as one sent within Cooldown. Zero disables suppression.
+	Cooldown time.Duration
+
+	// BatchWindow delays sending alerts, so that alerts arriving within the window are sent as single digest.
+	// Zero sends every alert immediately.
+	BatchWindow time.Duration
+
+	// Sinks receive every message.
+	Sinks []Sink
+
+	// OnError is called with errors of sending batched alerts, which cannot be returned to caller.
+	OnError func(error)
+
+	// Now returns current time, time.Now by default.
+	Now func() time.Time
+}
+
+// Notifier renders, deduplicates and batches alerts, and sends them to sinks. It is safe for concurrent use.
+type Notifier struct {
+	options Options
+
+	mu      sync.Mutex
+	sent    map[string]time.Time
+	pending []Alert
+	timer   *time.Timer
+}
+
+// New returns Notifier with given options. At least one sink is required.
+func New(options Options) (*Notifier, error) {
+	if len(options.Sinks) == 0 {
+		return nil, errors.New("notify: at least one sink is required")
+	}
+	if options.Template == nil {
+		options.Template = DefaultTemplate
+	}
+	if options.DigestTemplate == nil {
+		options.DigestTemplate = DefaultDigestTemplate
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return &Notifier{options: options, sent: map[string]time.Time{}}, nil
+}
+
+// Notify sends alerts, skipping ones suppressed by cooldown. With BatchWindow set, alerts are queued
+// and sent once the window, started by first queued alert, passes.
+func (n *Notifier) Notify(ctx context.Context, alerts ...Alert) error {
+	n.mu.Lock()
+	allowed := n.allow(alerts)
+	if len(allowed) == 0 {
+		n.mu.Unlock()
+		return nil
+	}
+
+	if n.options.BatchWindow <= 0 {
+		n.mu.Unlock()
+		return n.send(ctx, allowed)
+	}
+
+	n.pending = append(n.pending, allowed...)
+	if n.timer == nil {
+		n.timer = time.AfterFunc(n.options.BatchWindow, func() {
+			if err := n.Flush(context.Background()); err != nil && n.options.OnError != nil {
+				n.options.OnError(err)
+			}
+		})
+	}
+	n.mu.Unlock()
+	return nil
+}
+
+// Flush immediately sends queued alerts.
+func (n *Notifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	n.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	return n.send(ctx, pending)
+}
+
+// allow returns alerts not suppressed by cooldown, recording them as sent. Only the latest alert
+// about an item in a state is kept.
+func (n *Notifier) allow(alerts []Alert) []Alert {
+	now := n.options.Now()
+	var allowed []Alert
+	for _, alert := range alerts {
+		key := alert.ItemID + "/" + string(alert.State())
+		if last, ok := n.sent[key]; ok && n.options.Cooldown > 0 && now.Sub(last) < n.options.Cooldown {
+			continue
+		}
+		n.sent[key] = now
+		allowed = append(allowed, alert)
+	}
+
+	n.expire(now)
+	return allowed
+}
+
+// expire forgets alerts sent before cooldown, so that memory does not grow with every item ever seen.
+func (n *Notifier) expire(now time.Time) {
+	for key, last := range n.sent {
+		if now.Sub(last) >= n.options.Cooldown {
+			delete(n.sent, key)
+		}
+	}
+}
+
+// send renders alerts into single message and sends it to all sinks concurrently.
+func (n *Notifier) send(ctx context.Context, alerts []Alert) error {
+	var text string
+	var err error
+	if len(alerts) == 1 {
+		text, err = render(n.options.Template, alerts[0])
+	} else {
+		text, err = render(n.options.DigestTemplate, Digest{Alerts: alerts})
+	}
+	if err != nil {
+		return err
+	}
+	message := Message{Text: text, Alerts: alerts}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(n.options.Sinks))
+	for i, sink := range n.options.Sinks {
+		wg.Add(1)
+		go func(i int, sink Sink) {
+			defer wg.Done()
+			errs[i] = sink.Send(ctx, message)
+		}(i, sink)
+	}
+	wg.Wait()
+
+	sendErr := &SendError{}
+	for _, err := range errs {
+		if err != nil {
+			sendErr.Errors = append(sendErr.Errors, err)
+		}
+	}
+	if len(sendErr.Errors) > 0 {
+		return sendErr
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a Sink recording sent messages.
+type recorder struct {
+	mu       sync.Mutex
+	messages []Message
+	sent     chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{sent: make(chan struct{}, 10)}
+}
+
+func (r *recorder) Send(ctx context.Context, message Message) error {
+	r.mu.Lock()
+	r.messages = append(r.messages, message)
+	r.mu.Unlock()
+	r.sent <- struct{}{}
+	return nil
+}
+
+func (r *recorder) texts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var texts []string
+	for _, message := range r.messages {
+		texts = append(texts, message.Text)
+	}
+	return texts
+}
+
+func TestNotifier_Cooldown(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	sink := newRecorder()
+	tmpl, _ := NewTemplate("alert", `{{.ItemID}} {{.State}}`)
+
+	n, err := New(Options{Template: tmpl, Cooldown: time.Hour, Sinks: []Sink{sink}, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	steps := []struct {
+		after time.Duration
+		alert Alert
+	}{
+		{alert: alert("1", 2)},
+		{after: time.Minute, alert: alert("1", 1)}, // suppressed, same state
+		{after: time.Minute, alert: alert("1", 0)}, // state changed
+		{after: time.Minute, alert: alert("2", 1)}, // other item
+		{after: time.Hour, alert: alert("1", 3)},   // cooldown passed
+		{after: time.Minute, alert: alert("1", 0)}, // cooldown passed for sold out state too
+	}
+	for _, step := range steps {
+		now = now.Add(step.after)
+		if err := n.Notify(context.Background(), step.alert); err != nil {
+			t.Fatalf("Notifier.Notify returned error: %+v", err)
+		}
+	}
+
+	expected := []string{"1 available", "1 sold_out", "2 available", "1 available", "1 sold_out"}
+	if actual := sink.texts(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Sent: %+v, expected: %+v", actual, expected)
+	}
+}
+
+func TestNotifier_Batch(t *testing.T) {
+	sink := newRecorder()
+	n, err := New(Options{BatchWindow: 10 * time.Millisecond, Sinks: []Sink{sink}})
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	if err := n.Notify(context.Background(), alert("1", 1)); err != nil {
+		t.Fatalf("Notifier.Notify returned error: %+v", err)
+	}
+	if err := n.Notify(context.Background(), alert("2", 2), alert("3", 3)); err != nil {
+		t.Fatalf("Notifier.Notify returned error: %+v", err)
+	}
+
+	select {
+	case <-sink.sent:
+	case <-time.After(time.Second):
+		t.Fatal("Digest not sent.")
+	}
+
+	texts := sink.texts()
+	if len(texts) != 1 || !strings.HasPrefix(texts[0], "3 alerts:") {
+		t.Errorf("Sent: %+v, expected single digest of 3 alerts", texts)
+	}
+	if len(sink.messages[0].Alerts) != 3 {
+		t.Errorf("Message alerts: %+v, expected 3", sink.messages[0].Alerts)
+	}
+}
+
+func TestNotifier_Flush(t *testing.T) {
+	sink := newRecorder()
+	n, err := New(Options{BatchWindow: time.Hour, Sinks: []Sink{sink}})
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	if err := n.Notify(context.Background(), alert("1", 1)); err != nil {
+		t.Fatalf("Notifier.Notify returned error: %+v", err)
+	}
+	if texts := sink.texts(); len(texts) != 0 {
+		t.Fatalf("Sent: %+v, expected nothing before flush", texts)
+	}
+
+	if err := n.Flush(context.Background()); err != nil {
+		t.Fatalf("Notifier.Flush returned error: %+v", err)
+	}
+	if texts := sink.texts(); len(texts) != 1 || !strings.HasPrefix(texts[0], "store-1: 1 left") {
+		t.Errorf("Sent: %+v, expected single alert rendered with alert template", texts)
+	}
+
+	if err := n.Flush(context.Background()); err != nil {
+		t.Fatalf("Notifier.Flush returned error: %+v", err)
+	}
+	if texts := sink.texts(); len(texts) != 1 {
+		t.Errorf("Sent: %+v, expected no more messages", texts)
+	}
+}
+
+func TestNotifier_SinkErrors(t *testing.T) {
+	sink := newRecorder()
+	failing := SinkFunc(func(ctx context.Context, message Message) error {
+		return errors.New("unavailable")
+	})
+
+	n, err := New(Options{Sinks: []Sink{failing, sink}})
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	err = n.Notify(context.Background(), alert("1", 1))
+	var sendErr *SendError
+	if !errors.As(err, &sendErr) || len(sendErr.Errors) != 1 {
+		t.Errorf("Notifier.Notify returned error: %+v, expected SendError with single error", err)
+	}
+	if texts := sink.texts(); len(texts) != 1 {
+		t.Errorf("Sent: %+v, expected message to be delivered to working sink", texts)
+	}
+}
+
+func TestNew_NoSinks(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Error("New returned no error.")
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var b strings.Builder
+	if err := WriterSink(&b).Send(context.Background(), Message{Text: "text"}); err != nil {
+		t.Fatalf("WriterSink.Send returned error: %+v", err)
+	}
+	if b.String() != "text\n" {
+		t.Errorf("WriterSink wrote: %q, expected: %q", b.String(), "text\n")
+	}
+}
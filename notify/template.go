@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// Funcs are helpers available in templates created with NewTemplate:
+//
+//	price    formats Price with currency code, e.g. "3.99 EUR"
+//	pickup   formats pickup window of Alert in store's time zone, e.g. "19:00-19:30"
+//	distance formats distance in kilometers, e.g. "1.2 km", or "" if unknown
+//	discount formats discount of Alert's price against its value, e.g. "67%", or "" if unknown
+//	url      returns sharing URL of Alert, or Too Good To Go website if unknown
+var Funcs = template.FuncMap{
+	"price":    formatPrice,
+	"pickup":   formatPickup,
+	"distance": formatDistance,
+	"discount": formatDiscount,
+	"url":      sharingURL,
+}
+
+// defaultURL is linked in alerts without sharing URL.
+const defaultURL = "https://toogoodtogo.com"
+
+// NewTemplate parses text template with Funcs helpers. Alert templates are executed with Alert,
+// digest templates with Digest.
+func NewTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs).Parse(text)
+}
+
+// Digest is passed to digest template, batching multiple alerts.
+type Digest struct {
+	Alerts []Alert
+}
+
+// DefaultTemplate renders single alert.
+var DefaultTemplate = template.Must(NewTemplate("alert",
+	`{{.StoreName}}: {{.Available}} left for {{price .Price}}{{with pickup .}}, pickup {{.}}{{end}} {{url .}}`))
+
+// DefaultDigestTemplate renders multiple alerts, one per line.
+var DefaultDigestTemplate = template.Must(NewTemplate("digest",
+	`{{len .Alerts}} alerts:{{range .Alerts}}
+- {{.StoreName}}: {{.Available}} left for {{price .Price}}{{with pickup .}}, pickup {{.}}{{end}}{{end}}`))
+
+// render executes template with data.
+func render(tmpl *template.Template, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func formatPrice(price tgtg.Price) string {
+	if price.Code == "" {
+		return price.String()
+	}
+	return price.String() + " " + price.Code
+}
+
+func formatPickup(alert Alert) string {
+	window, err := alert.PickupWindow()
+	if err != nil || window.IsZero() {
+		return ""
+	}
+	return window.String()
+}
+
+func formatDistance(distance float64) string {
+	if distance <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f km", distance)
+}
+
+func formatDiscount(alert Alert) string {
+	item := tgtg.Item{PriceIncludingTaxes: alert.Price, ValueIncludingTaxes: alert.Value}
+	discount, err := item.Discount()
+	if err != nil || discount <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", discount)
+}
+
+func sharingURL(alert Alert) string {
+	if alert.SharingURL == "" {
+		return defaultURL
+	}
+	return alert.SharingURL
+}
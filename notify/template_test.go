@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func alert(itemID string, available int) Alert {
+	return Alert{
+		ItemID:    itemID,
+		StoreName: "store-" + itemID,
+		Available: available,
+		Price:     tgtg.Price{Code: "EUR", MinorUnits: 399, Decimals: 2},
+		Value:     tgtg.Price{Code: "EUR", MinorUnits: 1200, Decimals: 2},
+		Pickup: tgtg.PickupInterval{
+			Start: tgtg.Timestamp{Time: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+			End:   tgtg.Timestamp{Time: time.Date(2021, 12, 1, 18, 30, 0, 0, time.UTC)},
+		},
+		TimeZone: "Europe/Warsaw",
+		Distance: 1.25,
+	}
+}
+
+func TestNewTemplate(t *testing.T) {
+	withURL := alert("1", 2)
+	withURL.SharingURL = "https://share.toogoodtogo.com/item/1"
+
+	testCases := []struct {
+		title    string
+		text     string
+		alert    Alert
+		expected string
+	}{
+		{title: "price", text: `{{price .Price}}`, alert: alert("1", 2), expected: "3.99 EUR"},
+		{title: "pickup", text: `{{pickup .}}`, alert: alert("1", 2), expected: "19:00-19:30"},
+		{title: "no pickup", text: `[{{pickup .}}]`, alert: Alert{}, expected: "[]"},
+		{title: "distance", text: `{{distance .Distance}}`, alert: alert("1", 2), expected: "1.2 km"},
+		{title: "discount", text: `{{discount .}}`, alert: alert("1", 2), expected: "67%"},
+		{title: "sharing url", text: `{{url .}}`, alert: withURL, expected: "https://share.toogoodtogo.com/item/1"},
+		{title: "default url", text: `{{url .}}`, alert: alert("1", 2), expected: defaultURL},
+		{title: "state", text: `{{.State}}`, alert: alert("1", 0), expected: "sold_out"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			tmpl, err := NewTemplate(tc.title, tc.text)
+			if err != nil {
+				t.Fatalf("NewTemplate returned error: %+v", err)
+			}
+
+			actual, err := render(tmpl, tc.alert)
+			if err != nil {
+				t.Fatalf("render returned error: %+v", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("render returned: %q, expected: %q", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDefaultTemplates(t *testing.T) {
+	actual, err := render(DefaultTemplate, alert("1", 2))
+	if err != nil {
+		t.Fatalf("render returned error: %+v", err)
+	}
+	if expected := "store-1: 2 left for 3.99 EUR, pickup 19:00-19:30 " + defaultURL; actual != expected {
+		t.Errorf("DefaultTemplate rendered: %q, expected: %q", actual, expected)
+	}
+
+	actual, err = render(DefaultDigestTemplate, Digest{Alerts: []Alert{alert("1", 2), alert("2", 1)}})
+	if err != nil {
+		t.Fatalf("render returned error: %+v", err)
+	}
+	expected := "2 alerts:\n- store-1: 2 left for 3.99 EUR, pickup 19:00-19:30\n- store-2: 1 left for 3.99 EUR, pickup 19:00-19:30"
+	if actual != expected {
+		t.Errorf("DefaultDigestTemplate rendered: %q, expected: %q", actual, expected)
+	}
+}
+
+func TestFromItems(t *testing.T) {
+	at := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	items := tgtg.Items{
+		Item:           tgtg.Item{ItemID: "1"},
+		Store:          tgtg.Store{StoreID: "s1", StoreName: "store", StoreTimeZone: "Europe/Warsaw"},
+		ItemsAvailable: 3,
+		Distance:       2,
+	}
+
+	actual := FromItems(items, at)
+	if actual.ItemID != "1" || actual.StoreID != "s1" || actual.Available != 3 || actual.Distance != 2 || !actual.Time.Equal(at) {
+		t.Errorf("FromItems returned: %+v, expected alert of item 1", actual)
+	}
+
+	response := &tgtg.GetItemResponse{Item: tgtg.Item{ItemID: "1"}, SharingURL: "https://share"}
+	if actual := FromItemResponse(response, at); actual.SharingURL != "https://share" || actual.State() != StateSoldOut {
+		t.Errorf("FromItemResponse returned: %+v, expected sold out alert with sharing URL", actual)
+	}
+}
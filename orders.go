@@ -39,7 +39,7 @@ func (s *OrdersServiceOp) Active(ctx context.Context, activeOrdersRequest *Activ
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
 
 	activeOrdersResponse := &OrdersResponse{}
-	response, err := s.client.Do(ctx, req, activeOrdersResponse)
+	response, err := s.client.Do(withServiceMethod(ctx, "Orders.Active"), req, activeOrdersResponse)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -53,23 +53,25 @@ func (s *OrdersServiceOp) Inactive(ctx context.Context, inactiveOrdersRequest *I
 		return nil, nil, NewArgumentError("inactiveOrdersRequest", "must not be nil")
 	}
 
-	if inactiveOrdersRequest.UserID == "" {
+	// copy request, so caller's value is not mutated.
+	request := *inactiveOrdersRequest
+	if request.UserID == "" {
 		if s.client.UserID == "" {
 			return nil, nil, NewArgumentError("inactiveOrdersRequest.UserID", "must not be nil - client has no user id set - please log in using Auth service first or provide UserID in inactiveOrdersRequest")
 		}
 		// if UserID not passed, but we have already authenticated with API, use user id stored in client.
-		inactiveOrdersRequest.UserID = s.client.UserID
+		request.UserID = s.client.UserID
 	}
 
 	url := fmt.Sprintf("%s/inactive", ordersBasePath)
-	req, err := s.client.NewRequest(http.MethodPost, url, inactiveOrdersRequest)
+	req, err := s.client.NewRequest(http.MethodPost, url, &request)
 	if err != nil {
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
 
 	inactiveOrdersResponse := &OrdersResponse{}
-	response, err := s.client.Do(ctx, req, inactiveOrdersResponse)
+	response, err := s.client.Do(withServiceMethod(ctx, "Orders.Inactive"), req, inactiveOrdersResponse)
 	if err != nil {
 		return nil, nil, err
 	}
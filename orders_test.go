@@ -160,8 +160,11 @@ func TestOrdersService_Inactive(t *testing.T) {
 		}
 
 		testMethod(t, r, http.MethodPost)
-		if !reflect.DeepEqual(req, inactiveRequest) {
-			t.Errorf("Request body: %+v, expected: %+v", req, inactiveRequest)
+		// UserID is taken from the client, without mutating caller's request.
+		expectedRequest := *inactiveRequest
+		expectedRequest.UserID = "1"
+		if !reflect.DeepEqual(req, &expectedRequest) {
+			t.Errorf("Request body: %+v, expected: %+v", req, &expectedRequest)
 		}
 
 		fmt.Fprintf(w, `
@@ -1,7 +1,5 @@
 package tgtg
 
-import "time"
-
 // ActiveOrdersRequest represents a request body to obtain user's active orders.
 type ActiveOrdersRequest struct {
 	UserID string `json:"user_id"`
@@ -15,7 +13,7 @@ type InactiveOrdersRequest struct {
 
 // OrdersResponse represents a response body containing Orders details.
 type OrdersResponse struct {
-	CurrentTime time.Time `json:"current_time"`
+	CurrentTime Timestamp `json:"current_time"`
 	HasMore     bool      `json:"has_more"`
 	Orders      []Order   `json:"orders"`
 }
@@ -30,7 +28,7 @@ type Paging struct {
 type Order struct {
 	OrderID                    string         `json:"order_id"`
 	State                      string         `json:"state"`
-	CancelUntil                time.Time      `json:"cancel_until"`
+	CancelUntil                Timestamp      `json:"cancel_until"`
 	RedeemInterval             PickupInterval `json:"redeem_interval"`
 	PickupInterval             PickupInterval `json:"pickup_interval"`
 	Quantity                   int            `json:"quantity"`
@@ -40,7 +38,7 @@ type Order struct {
 	SalesTaxes                 []SalesTaxes   `json:"sales_taxes"`
 	PickupLocation             PickupLocation `json:"pickup_location"`
 	IsRated                    bool           `json:"is_rated"`
-	TimeOfPurchase             time.Time      `json:"time_of_purchase"`
+	TimeOfPurchase             Timestamp      `json:"time_of_purchase"`
 	StoreID                    string         `json:"store_id"`
 	StoreName                  string         `json:"store_name"`
 	StoreBranch                string         `json:"store_branch"`
@@ -0,0 +1,124 @@
+package tgtg
+
+import (
+	"time"
+)
+
+// Clock returns current time. It is used by PickupWindow for relative time calculations,
+// so fixed time can be injected in tests. Pass time.Now to use system clock.
+type Clock func() time.Time
+
+// PickupWindow represents a PickupInterval expressed in the store's time zone.
+type PickupWindow struct {
+	// Start of the pickup window in the store's time zone.
+	Start time.Time
+
+	// End of the pickup window in the store's time zone.
+	End time.Time
+
+	// Location is the store's time zone.
+	Location *time.Location
+
+	clock Clock
+}
+
+// NewPickupWindow creates a PickupWindow from given PickupInterval in given IANA time zone (e.g. "Europe/Warsaw").
+// Empty time zone is treated as UTC. If clock is nil, time.Now is used.
+func NewPickupWindow(interval PickupInterval, timeZone string, clock Clock) (PickupWindow, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return PickupWindow{}, NewArgumentError("timeZone", err.Error())
+	}
+
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return PickupWindow{
+		Start:    interval.Start.In(location),
+		End:      interval.End.In(location),
+		Location: location,
+		clock:    clock,
+	}, nil
+}
+
+// PickupWindow returns pickup window of the Items in the store's time zone.
+func (i Items) PickupWindow(clock Clock) (PickupWindow, error) {
+	return NewPickupWindow(i.PickupInterval, i.Store.StoreTimeZone, clock)
+}
+
+// PickupWindow returns pickup window of the item in the store's time zone.
+func (r GetItemResponse) PickupWindow(clock Clock) (PickupWindow, error) {
+	return NewPickupWindow(r.PickupInterval, r.Store.StoreTimeZone, clock)
+}
+
+// PickupWindow returns pickup window of the Order in given time zone. Orders do not carry store's
+// time zone, it can be obtained from Store.StoreTimeZone of the ordered item.
+func (o Order) PickupWindow(timeZone string, clock Clock) (PickupWindow, error) {
+	return NewPickupWindow(o.PickupInterval, timeZone, clock)
+}
+
+// IsZero reports whether the pickup window is not set.
+func (w PickupWindow) IsZero() bool {
+	return w.Start.IsZero() && w.End.IsZero()
+}
+
+// String returns the pickup window in store's local time, e.g. "19:00-19:30".
+func (w PickupWindow) String() string {
+	return w.Start.Format("15:04") + "-" + w.End.Format("15:04")
+}
+
+// IsToday reports whether the pickup window starts today in the store's time zone.
+func (w PickupWindow) IsToday() bool {
+	return sameDay(w.Start, w.now())
+}
+
+// IsTomorrow reports whether the pickup window starts tomorrow in the store's time zone.
+func (w PickupWindow) IsTomorrow() bool {
+	return sameDay(w.Start, w.now().AddDate(0, 0, 1))
+}
+
+// IsOpen reports whether the pickup window is currently open.
+func (w PickupWindow) IsOpen() bool {
+	now := w.now()
+	return !now.Before(w.Start) && now.Before(w.End)
+}
+
+// UntilOpen returns time left until the pickup window opens, or zero if it already opened.
+func (w PickupWindow) UntilOpen() time.Duration {
+	if d := w.Start.Sub(w.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// UntilClose returns time left until the pickup window closes, or zero if it already closed.
+func (w PickupWindow) UntilClose() time.Duration {
+	if d := w.End.Sub(w.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// now returns current time from the clock in the store's time zone.
+func (w PickupWindow) now() time.Time {
+	clock := w.clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	location := w.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	return clock().In(location)
+}
+
+// sameDay reports whether a and b fall on the same calendar day in a's location.
+func sameDay(a, b time.Time) bool {
+	b = b.In(a.Location())
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,116 @@
+package tgtg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func fixedClock(t time.Time) Clock {
+	return func() time.Time { return t }
+}
+
+func TestItems_PickupWindow(t *testing.T) {
+	items := Items{
+		Store: Store{StoreTimeZone: "Europe/Warsaw"},
+		PickupInterval: PickupInterval{
+			Start: Timestamp{time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+			End:   Timestamp{time.Date(2021, 12, 1, 18, 30, 0, 0, time.UTC)},
+		},
+	}
+
+	testCases := []struct {
+		title      string
+		now        time.Time
+		today      bool
+		tomorrow   bool
+		open       bool
+		untilOpen  time.Duration
+		untilClose time.Duration
+	}{
+		{
+			title:      "same day, before opening",
+			now:        time.Date(2021, 12, 1, 17, 0, 0, 0, time.UTC),
+			today:      true,
+			untilOpen:  time.Hour,
+			untilClose: 90 * time.Minute,
+		},
+		{
+			title:      "open",
+			now:        time.Date(2021, 12, 1, 18, 10, 0, 0, time.UTC),
+			today:      true,
+			open:       true,
+			untilClose: 20 * time.Minute,
+		},
+		{
+			title: "closed",
+			now:   time.Date(2021, 12, 1, 19, 0, 0, 0, time.UTC),
+			today: true,
+		},
+		{
+			// 23:30 UTC on 30th is already 1st in Warsaw.
+			title:      "today in store's zone, yesterday in UTC",
+			now:        time.Date(2021, 11, 30, 23, 30, 0, 0, time.UTC),
+			today:      true,
+			untilOpen:  18*time.Hour + 30*time.Minute,
+			untilClose: 19 * time.Hour,
+		},
+		{
+			title:      "tomorrow",
+			now:        time.Date(2021, 11, 30, 12, 0, 0, 0, time.UTC),
+			tomorrow:   true,
+			untilOpen:  30 * time.Hour,
+			untilClose: 30*time.Hour + 30*time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			window, err := items.PickupWindow(fixedClock(tc.now))
+			if err != nil {
+				t.Fatalf("Items.PickupWindow returned error: %+v", err)
+			}
+
+			if actual := window.String(); actual != "19:00-19:30" {
+				t.Errorf("PickupWindow.String returned: %+v, expected: 19:00-19:30", actual)
+			}
+			if actual := window.IsToday(); actual != tc.today {
+				t.Errorf("PickupWindow.IsToday returned: %+v, expected: %+v", actual, tc.today)
+			}
+			if actual := window.IsTomorrow(); actual != tc.tomorrow {
+				t.Errorf("PickupWindow.IsTomorrow returned: %+v, expected: %+v", actual, tc.tomorrow)
+			}
+			if actual := window.IsOpen(); actual != tc.open {
+				t.Errorf("PickupWindow.IsOpen returned: %+v, expected: %+v", actual, tc.open)
+			}
+			if actual := window.UntilOpen(); actual != tc.untilOpen {
+				t.Errorf("PickupWindow.UntilOpen returned: %+v, expected: %+v", actual, tc.untilOpen)
+			}
+			if actual := window.UntilClose(); actual != tc.untilClose {
+				t.Errorf("PickupWindow.UntilClose returned: %+v, expected: %+v", actual, tc.untilClose)
+			}
+		})
+	}
+}
+
+func TestOrder_PickupWindow(t *testing.T) {
+	order := Order{
+		PickupInterval: PickupInterval{
+			Start: Timestamp{time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+			End:   Timestamp{time.Date(2021, 12, 1, 18, 30, 0, 0, time.UTC)},
+		},
+	}
+
+	window, err := order.PickupWindow("", nil)
+	if err != nil {
+		t.Fatalf("Order.PickupWindow returned error: %+v", err)
+	}
+	if actual := window.String(); actual != "18:00-18:30" {
+		t.Errorf("PickupWindow.String returned: %+v, expected: 18:00-18:30", actual)
+	}
+
+	_, err = order.PickupWindow("Not/AZone", nil)
+	if err == nil || !strings.Contains(err.Error(), "timeZone") {
+		t.Errorf("Error: %+v did not contain timeZone", err)
+	}
+}
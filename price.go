@@ -0,0 +1,198 @@
+package tgtg
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// currencySymbols maps ISO 4217 currency codes used by Too Good To Go to their symbols.
+var currencySymbols = map[string]string{
+	"AUD": "A$",
+	"CAD": "CA$",
+	"CHF": "CHF",
+	"CZK": "Kč",
+	"DKK": "kr.",
+	"EUR": "€",
+	"GBP": "£",
+	"HUF": "Ft",
+	"NOK": "kr",
+	"PLN": "zł",
+	"SEK": "kr",
+	"USD": "$",
+}
+
+// numberFormat describes how a locale formats monetary amounts. Spaces are non-breaking.
+type numberFormat struct {
+	decimal      string
+	group        string
+	symbolSuffix bool
+}
+
+// localeFormats maps language part of a locale to its monetary number format.
+var localeFormats = map[string]numberFormat{
+	"en": {decimal: ".", group: ",", symbolSuffix: false},
+	"de": {decimal: ",", group: ".", symbolSuffix: true},
+	"fr": {decimal: ",", group: "\u202f", symbolSuffix: true},
+	"it": {decimal: ",", group: ".", symbolSuffix: true},
+	"es": {decimal: ",", group: ".", symbolSuffix: true},
+	"pt": {decimal: ",", group: ".", symbolSuffix: true},
+	"nl": {decimal: ",", group: ".", symbolSuffix: false},
+	"da": {decimal: ",", group: ".", symbolSuffix: true},
+	"nb": {decimal: ",", group: "\u00a0", symbolSuffix: true},
+	"sv": {decimal: ",", group: "\u00a0", symbolSuffix: true},
+	"pl": {decimal: ",", group: "\u00a0", symbolSuffix: true},
+	"cs": {decimal: ",", group: "\u00a0", symbolSuffix: true},
+	"hu": {decimal: ",", group: "\u00a0", symbolSuffix: true},
+}
+
+// Rat returns exact value of the Price as a rational number.
+// Negative Decimals scale MinorUnits up, e.g. 5 with -2 decimals is 500.
+func (p Price) Rat() *big.Rat {
+	scale := p.Decimals
+	if scale < 0 {
+		scale = -scale
+	}
+	power := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+
+	minorUnits := big.NewInt(int64(p.MinorUnits))
+	if p.Decimals < 0 {
+		return new(big.Rat).SetInt(minorUnits.Mul(minorUnits, power))
+	}
+	return new(big.Rat).SetFrac(minorUnits, power)
+}
+
+// Float64 returns the nearest float64 value of the Price. Use Rat or String where exactness matters.
+func (p Price) Float64() float64 {
+	f, _ := p.Rat().Float64()
+	return f
+}
+
+// String returns exact decimal representation of the Price amount, e.g. "3.99", without currency.
+func (p Price) String() string {
+	return p.formatAmount(".", "")
+}
+
+// Format returns the Price formatted for given locale (e.g. "en-GB", "de_DE", "it") including currency symbol.
+// Unknown locales fall back to English formatting and unknown currencies to their ISO code.
+func (p Price) Format(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+
+	format, ok := localeFormats[language]
+	if !ok {
+		format = localeFormats["en"]
+	}
+
+	symbol, ok := currencySymbols[p.Code]
+	if !ok {
+		symbol = p.Code
+	}
+
+	amount := p.formatAmount(format.decimal, format.group)
+	if format.symbolSuffix {
+		return amount + "\u00a0" + symbol
+	}
+	if strings.HasPrefix(amount, "-") {
+		return "-" + symbol + amount[1:]
+	}
+	return symbol + amount
+}
+
+// Cmp compares p and q and returns -1 if p < q, 0 if p == q and +1 if p > q.
+// Prices of different currencies cannot be compared, CurrencyMismatchError is returned then.
+func (p Price) Cmp(q Price) (int, error) {
+	if p.Code != q.Code {
+		return 0, NewCurrencyMismatchError(p.Code, q.Code)
+	}
+	return p.Rat().Cmp(q.Rat()), nil
+}
+
+// Add returns sum of p and q, expressed with greater precision of the two.
+// Prices of different currencies cannot be added, CurrencyMismatchError is returned then.
+func (p Price) Add(q Price) (Price, error) {
+	if p.Code != q.Code {
+		return Price{}, NewCurrencyMismatchError(p.Code, q.Code)
+	}
+	p, q = p.withDecimals(q.Decimals), q.withDecimals(p.Decimals)
+	return Price{Code: p.Code, Decimals: p.Decimals, MinorUnits: p.MinorUnits + q.MinorUnits}, nil
+}
+
+// Sub returns difference of p and q, expressed with greater precision of the two.
+// Prices of different currencies cannot be subtracted, CurrencyMismatchError is returned then.
+func (p Price) Sub(q Price) (Price, error) {
+	q.MinorUnits = -q.MinorUnits
+	return p.Add(q)
+}
+
+// withDecimals rescales the Price to given number of decimals, if it is greater than current one.
+func (p Price) withDecimals(decimals int) Price {
+	for p.Decimals < decimals {
+		p.MinorUnits *= 10
+		p.Decimals++
+	}
+	return p
+}
+
+// formatAmount formats Price amount with given decimal and thousands group separators.
+func (p Price) formatAmount(decimal, group string) string {
+	minorUnits := p.MinorUnits
+	sign := ""
+	if minorUnits < 0 {
+		sign = "-"
+		minorUnits = -minorUnits
+	}
+
+	digits := strconv.Itoa(minorUnits)
+	decimals := p.Decimals
+	if decimals < 0 {
+		if minorUnits != 0 {
+			digits += strings.Repeat("0", -decimals)
+		}
+		decimals = 0
+	}
+	if decimals > 0 && len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer, fraction := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+
+	if group != "" {
+		var grouped strings.Builder
+		for i, digit := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				grouped.WriteString(group)
+			}
+			grouped.WriteRune(digit)
+		}
+		integer = grouped.String()
+	}
+
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + decimal + fraction
+}
+
+// Savings returns the difference between ValueIncludingTaxes and PriceIncludingTaxes of the Item,
+// which is the amount saved by buying it.
+func (i Item) Savings() (Price, error) {
+	return i.ValueIncludingTaxes.Sub(i.PriceIncludingTaxes)
+}
+
+// Discount returns the discount of PriceIncludingTaxes in relation to ValueIncludingTaxes of the Item
+// as a percentage, e.g. 66.67. Zero is returned if the Item has no value set.
+func (i Item) Discount() (float64, error) {
+	savings, err := i.Savings()
+	if err != nil {
+		return 0, err
+	}
+	if i.ValueIncludingTaxes.MinorUnits == 0 {
+		return 0, nil
+	}
+
+	discount := new(big.Rat).Quo(savings.Rat(), i.ValueIncludingTaxes.Rat())
+	percentage, _ := discount.Mul(discount, big.NewRat(100, 1)).Float64()
+	return percentage, nil
+}
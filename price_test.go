@@ -0,0 +1,149 @@
+package tgtg
+
+import (
+	"testing"
+)
+
+func TestPrice_String(t *testing.T) {
+	testCases := []struct {
+		title    string
+		price    Price
+		expected string
+	}{
+		{title: "two decimals", price: Price{Code: "EUR", Decimals: 2, MinorUnits: 399}, expected: "3.99"},
+		{title: "less minor units than decimals", price: Price{Code: "EUR", Decimals: 2, MinorUnits: 5}, expected: "0.05"},
+		{title: "no decimals", price: Price{Code: "HUF", Decimals: 0, MinorUnits: 1290}, expected: "1290"},
+		{title: "negative", price: Price{Code: "EUR", Decimals: 2, MinorUnits: -1250}, expected: "-12.50"},
+		{title: "zero", price: Price{}, expected: "0"},
+		{title: "negative decimals", price: Price{Code: "HUF", Decimals: -2, MinorUnits: 15}, expected: "1500"},
+		{title: "negative decimals zero", price: Price{Code: "HUF", Decimals: -2}, expected: "0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := tc.price.String(); actual != tc.expected {
+				t.Errorf("Price.String returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPrice_Rat(t *testing.T) {
+	testCases := []struct {
+		title    string
+		price    Price
+		expected string
+	}{
+		{title: "two decimals", price: Price{Code: "EUR", Decimals: 2, MinorUnits: 399}, expected: "399/100"},
+		{title: "no decimals", price: Price{Code: "HUF", Decimals: 0, MinorUnits: 1290}, expected: "1290/1"},
+		{title: "negative decimals", price: Price{Code: "HUF", Decimals: -2, MinorUnits: -15}, expected: "-1500/1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := tc.price.Rat().String(); actual != tc.expected {
+				t.Errorf("Price.Rat returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPrice_Format(t *testing.T) {
+	testCases := []struct {
+		title    string
+		price    Price
+		locale   string
+		expected string
+	}{
+		{title: "english pounds", price: Price{Code: "GBP", Decimals: 2, MinorUnits: 350}, locale: "en-GB", expected: "£3.50"},
+		{title: "english grouping", price: Price{Code: "USD", Decimals: 2, MinorUnits: 123456789}, locale: "en_US", expected: "$1,234,567.89"},
+		{title: "german euros", price: Price{Code: "EUR", Decimals: 2, MinorUnits: 123456}, locale: "de-DE", expected: "1.234,56\u00a0€"},
+		{title: "polish zloty", price: Price{Code: "PLN", Decimals: 2, MinorUnits: 1999}, locale: "pl", expected: "19,99\u00a0zł"},
+		{title: "french grouping", price: Price{Code: "EUR", Decimals: 2, MinorUnits: 123456}, locale: "fr-FR", expected: "1\u202f234,56\u00a0€"},
+		{title: "dutch negative", price: Price{Code: "EUR", Decimals: 2, MinorUnits: -250}, locale: "nl-NL", expected: "-€2,50"},
+		{title: "negative decimals grouping", price: Price{Code: "HUF", Decimals: -3, MinorUnits: 12}, locale: "hu", expected: "12\u00a0000\u00a0Ft"},
+		{title: "unknown locale and currency", price: Price{Code: "XYZ", Decimals: 1, MinorUnits: 15}, locale: "xx", expected: "XYZ1.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := tc.price.Format(tc.locale); actual != tc.expected {
+				t.Errorf("Price.Format returned: %q, expected: %q", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPrice_Cmp(t *testing.T) {
+	a := Price{Code: "EUR", Decimals: 2, MinorUnits: 350}
+	b := Price{Code: "EUR", Decimals: 1, MinorUnits: 35}
+	c := Price{Code: "EUR", Decimals: 2, MinorUnits: 351}
+
+	if actual, err := a.Cmp(b); err != nil || actual != 0 {
+		t.Errorf("Price.Cmp returned: %+v, %+v, expected: 0, nil", actual, err)
+	}
+
+	if actual, err := a.Cmp(c); err != nil || actual != -1 {
+		t.Errorf("Price.Cmp returned: %+v, %+v, expected: -1, nil", actual, err)
+	}
+
+	if _, err := a.Cmp(Price{Code: "GBP"}); err == nil {
+		t.Error("Price.Cmp returned no error for currency mismatch.")
+	}
+}
+
+func TestPrice_AddSub(t *testing.T) {
+	a := Price{Code: "EUR", Decimals: 2, MinorUnits: 350}
+	b := Price{Code: "EUR", Decimals: 1, MinorUnits: 15}
+
+	sum, err := a.Add(b)
+	if err != nil {
+		t.Fatalf("Price.Add returned error: %+v", err)
+	}
+	expected := Price{Code: "EUR", Decimals: 2, MinorUnits: 500}
+	if sum != expected {
+		t.Errorf("Price.Add returned: %+v, expected: %+v", sum, expected)
+	}
+
+	difference, err := b.Sub(a)
+	if err != nil {
+		t.Fatalf("Price.Sub returned error: %+v", err)
+	}
+	expected = Price{Code: "EUR", Decimals: 2, MinorUnits: -200}
+	if difference != expected {
+		t.Errorf("Price.Sub returned: %+v, expected: %+v", difference, expected)
+	}
+
+	_, err = a.Add(Price{Code: "DKK", Decimals: 2, MinorUnits: 1})
+	if _, ok := err.(*CurrencyMismatchError); !ok {
+		t.Errorf("Price.Add returned: %+v, expected: CurrencyMismatchError", err)
+	}
+}
+
+func TestItem_Discount(t *testing.T) {
+	item := Item{
+		ValueIncludingTaxes: Price{Code: "EUR", Decimals: 2, MinorUnits: 1200},
+		PriceIncludingTaxes: Price{Code: "EUR", Decimals: 2, MinorUnits: 399},
+	}
+
+	savings, err := item.Savings()
+	if err != nil {
+		t.Fatalf("Item.Savings returned error: %+v", err)
+	}
+	if expected := "8.01"; savings.String() != expected {
+		t.Errorf("Item.Savings returned: %+v, expected: %+v", savings, expected)
+	}
+
+	discount, err := item.Discount()
+	if err != nil {
+		t.Fatalf("Item.Discount returned error: %+v", err)
+	}
+	if discount < 66.74 || discount > 66.76 {
+		t.Errorf("Item.Discount returned: %+v, expected: ~66.75", discount)
+	}
+
+	discount, err = Item{}.Discount()
+	if err != nil || discount != 0 {
+		t.Errorf("Item.Discount returned: %+v, %+v, expected: 0, nil", discount, err)
+	}
+}
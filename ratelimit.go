@@ -0,0 +1,34 @@
+package tgtg
+
+import (
+	"context"
+	"net/http"
+)
+
+// RateLimiter limits rate of requests sent to Too Good To Go API. Wait blocks until request
+// is allowed to be sent or ctx is done. *rate.Limiter from golang.org/x/time/rate satisfies it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// SetRateLimiter is a ClientOption for limiting rate of requests sent by the client, including retries.
+// The same RateLimiter can be shared by multiple clients to limit their combined rate.
+func SetRateLimiter(limiter RateLimiter) ClientOption {
+	return func(c *Client) error {
+		if limiter == nil {
+			return NewArgumentError("limiter", "must not be nil")
+		}
+		c.limiter = limiter
+		return nil
+	}
+}
+
+// rateLimiting is a Middleware waiting for client's RateLimiter before sending every request.
+func (c *Client) rateLimiting(next Handler) Handler {
+	return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return next(ctx, method, req)
+	}
+}
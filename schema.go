@@ -0,0 +1,187 @@
+package tgtg
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaReport describes differences between JSON response returned by Too Good To Go API
+// and the Go type it was decoded into. Too Good To Go API is undocumented and changes silently,
+// SchemaReport allows noticing such changes early.
+type SchemaReport struct {
+	// Endpoint that returned the response, e.g. "POST item/v7/".
+	Endpoint string
+
+	// Unknown lists JSON fields present in the response that have no matching Go field, e.g. "items[].item.new_field".
+	Unknown []string
+
+	// Missing lists Go fields that were absent in the response, e.g. "items[].store.website".
+	Missing []string
+
+	// RawJSON is the response body, set only if raw JSON capture is enabled.
+	RawJSON json.RawMessage
+}
+
+// HasDrift reports whether any unknown or missing fields were found.
+func (r *SchemaReport) HasDrift() bool {
+	return len(r.Unknown) > 0 || len(r.Missing) > 0
+}
+
+// SchemaReporter is called with SchemaReport of decoded responses.
+type SchemaReporter func(*SchemaReport)
+
+// SetStrictDecoding is a ClientOption enabling detection of schema drift in Too Good To Go API responses.
+// Responses are still decoded leniently and calls do not fail, instead reporter is called whenever
+// unknown or missing fields are found. If captureRawJSON is set, reporter is called for every decoded
+// response, with raw response body attached.
+func SetStrictDecoding(reporter SchemaReporter, captureRawJSON bool) ClientOption {
+	return func(c *Client) error {
+		if reporter == nil {
+			return NewArgumentError("reporter", "must not be nil")
+		}
+		c.schemaReporter = reporter
+		c.captureRawJSON = captureRawJSON
+		return nil
+	}
+}
+
+// reportSchema compares JSON data against type of v it was decoded into
+// and calls client's SchemaReporter if needed.
+func (c *Client) reportSchema(req *http.Request, data []byte, v interface{}) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return
+	}
+
+	diff := &schemaDiff{unknown: map[string]bool{}, missing: map[string]bool{}}
+	diff.compare("", reflect.TypeOf(v), value)
+
+	report := &SchemaReport{
+		Endpoint: req.Method + " " + c.endpoint(req),
+		Unknown:  diff.sorted(diff.unknown),
+		Missing:  diff.sorted(diff.missing),
+	}
+	if c.captureRawJSON {
+		report.RawJSON = json.RawMessage(data)
+	}
+
+	if report.HasDrift() || c.captureRawJSON {
+		c.schemaReporter(report)
+	}
+}
+
+// schemaDiff collects unknown and missing field paths.
+type schemaDiff struct {
+	unknown map[string]bool
+	missing map[string]bool
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// compare walks Go type t alongside decoded JSON value, recording fields present on only one side.
+func (d *schemaDiff) compare(path string, t reflect.Type, value interface{}) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Types decoding themselves, e.g. Timestamp, are treated as leaves.
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		known := map[string]bool{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+
+			key, found := lookupKey(object, name)
+			if !found {
+				d.missing[joinPath(path, name)] = true
+				continue
+			}
+			known[key] = true
+			d.compare(joinPath(path, name), field.Type, object[key])
+		}
+
+		for key := range object {
+			if !known[key] {
+				d.unknown[joinPath(path, key)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for _, element := range array {
+			d.compare(path+"[]", t.Elem(), element)
+		}
+	}
+}
+
+// sorted returns sorted keys of set, or nil if it is empty.
+func (d *schemaDiff) sorted(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(set))
+	for path := range set {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// jsonFieldName returns JSON name of struct field, or false if field is not encoded.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// lookupKey finds JSON object key matching field name, preferring exact match
+// and falling back to case-insensitive one, same as encoding/json does.
+func lookupKey(object map[string]interface{}, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// joinPath joins JSON field path with a field name.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,109 @@
+package tgtg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSetStrictDecoding(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var reports []*SchemaReport
+	err := SetStrictDecoding(func(r *SchemaReport) { reports = append(reports, r) }, false)(client)
+	if err != nil {
+		t.Fatalf("SetStrictDecoding returned error: %+v", err)
+	}
+
+	mux.HandleFunc("/auth/v3/authByEmail", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"polling_id": "polling_id", "state": "state"}`)
+	})
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+		{
+			"access_token": "access_token",
+			"ACCESS_TOKEN_TTL_SECONDS": 172800,
+			"new_field": {"nested": true}
+		}
+		`)
+	})
+
+	_, _, err = client.Auth.Login(context.Background(), &LoginRequest{})
+	if err != nil {
+		t.Fatalf("Auth.Login returned error: %+v", err)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("SchemaReporter called: %+v, expected no calls", reports)
+	}
+
+	actual, _, err := client.Auth.Refresh(context.Background(), &RefreshTokensRequest{})
+	if err != nil {
+		t.Fatalf("Auth.Refresh returned error: %+v", err)
+	}
+	if actual.AccessTokenTTL != 172800 {
+		t.Errorf("Auth.Refresh returned: %+v, expected AccessTokenTTL: 172800", actual)
+	}
+
+	expected := []*SchemaReport{
+		{
+			Endpoint: "POST auth/v3/token/refresh",
+			Unknown:  []string{"new_field"},
+			Missing:  []string{"refresh_token"},
+		},
+	}
+	if len(reports) != 1 {
+		t.Fatalf("SchemaReporter called %d times, expected once", len(reports))
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Errorf("SchemaReporter called with: %+v, expected: %+v", *reports[0], *expected[0])
+	}
+}
+
+func TestSetStrictDecoding_Nested(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var report *SchemaReport
+	err := SetStrictDecoding(func(r *SchemaReport) { report = r }, true)(client)
+	if err != nil {
+		t.Fatalf("SetStrictDecoding returned error: %+v", err)
+	}
+
+	body := `{"has_more": false, "current_time": "", "orders": [{"order_id": "1", "unknown": 1}, {"order_id": "2"}]}`
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	_, _, err = client.Orders.Active(context.Background(), &ActiveOrdersRequest{UserID: "1"})
+	if err != nil {
+		t.Fatalf("Orders.Active returned error: %+v", err)
+	}
+
+	if report == nil {
+		t.Fatal("SchemaReporter not called.")
+	}
+	if string(report.RawJSON) != body {
+		t.Errorf("SchemaReport.RawJSON: %+v, expected: %+v", string(report.RawJSON), body)
+	}
+	if expected := []string{"orders[].unknown"}; !reflect.DeepEqual(report.Unknown, expected) {
+		t.Errorf("SchemaReport.Unknown: %+v, expected: %+v", report.Unknown, expected)
+	}
+	for _, missing := range report.Missing {
+		if missing == "current_time" || missing == "orders[].order_id" {
+			t.Errorf("SchemaReport.Missing: %+v, should not contain %+v", report.Missing, missing)
+		}
+	}
+	if len(report.Missing) == 0 || report.Missing[0] != "orders[].can_show_best_before_explainer" {
+		t.Errorf("SchemaReport.Missing: %+v, expected to start with orders[].can_show_best_before_explainer", report.Missing)
+	}
+}
+
+func TestSetStrictDecoding_ArgumentError(t *testing.T) {
+	_, err := New(nil, SetStrictDecoding(nil, false))
+	if err == nil {
+		t.Fatal("New returned no error.")
+	}
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"context"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+const defaultPageSize = 20
+
+// InactiveOrders fetches full inactive order history of client's user, page by page.
+// Page size defaults to 20 if not positive.
+func InactiveOrders(ctx context.Context, client *tgtg.Client, pageSize int) ([]tgtg.Order, error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	var orders []tgtg.Order
+	for page := 0; ; page++ {
+		response, _, err := client.Orders.Inactive(ctx, &tgtg.InactiveOrdersRequest{
+			Paging: tgtg.Paging{Page: page, Size: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, response.Orders...)
+		if !response.HasMore || len(response.Orders) == 0 {
+			return orders, nil
+		}
+	}
+}
+
+// ItemValues fetches original value of single bag, including taxes, of every item ordered.
+// Items which could not be fetched, e.g. no longer offered, are reported in returned *tgtg.ItemsError,
+// while values of the remaining ones are still returned.
+func ItemValues(ctx context.Context, client *tgtg.Client, orders []tgtg.Order) (map[string]tgtg.Price, error) {
+	var itemIDs []string
+	for _, order := range orders {
+		if order.ItemID != "" {
+			itemIDs = append(itemIDs, order.ItemID)
+		}
+	}
+
+	responses, err := tgtg.GetMany(ctx, client.Items, itemIDs, nil)
+
+	values := make(map[string]tgtg.Price, len(responses))
+	for itemID, response := range responses {
+		values[itemID] = response.Item.ValueIncludingTaxes
+	}
+	return values, err
+}
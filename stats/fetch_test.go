@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func setup(t *testing.T) (*tgtg.Client, *http.ServeMux) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := tgtg.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.SetAuthContext("access", "refresh", "1")
+	return client, mux
+}
+
+func TestInactiveOrders(t *testing.T) {
+	client, mux := setup(t)
+
+	var pages []int
+	mux.HandleFunc("/order/v6/inactive", func(w http.ResponseWriter, r *http.Request) {
+		request := &tgtg.InactiveOrdersRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			t.Errorf("Decode json: %+v", err)
+			return
+		}
+		pages = append(pages, request.Paging.Page)
+
+		hasMore := request.Paging.Page < 2
+		fmt.Fprintf(w, `{"has_more": %t, "orders": [{"order_id": "%d"}]}`, hasMore, request.Paging.Page)
+	})
+
+	orders, err := InactiveOrders(context.Background(), client, 1)
+	if err != nil {
+		t.Fatalf("InactiveOrders returned error: %+v", err)
+	}
+
+	if expected := []int{0, 1, 2}; !reflect.DeepEqual(pages, expected) {
+		t.Errorf("InactiveOrders requested pages: %+v, expected: %+v", pages, expected)
+	}
+	if len(orders) != 3 || orders[2].OrderID != "2" {
+		t.Errorf("InactiveOrders returned: %+v, expected 3 orders", orders)
+	}
+}
+
+func TestItemValues(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/item/v7/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"item": {"value_including_taxes": {"code": "PLN", "minor_units": 3000, "decimals": 2}}}`)
+	})
+	mux.HandleFunc("/item/v7/2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	orders := []tgtg.Order{{ItemID: "1"}, {ItemID: "2"}, {ItemID: "1"}}
+	values, err := ItemValues(context.Background(), client, orders)
+
+	var itemsErr *tgtg.ItemsError
+	if !errors.As(err, &itemsErr) || len(itemsErr.Errors) != 1 || itemsErr.Errors["2"] == nil {
+		t.Errorf("ItemValues returned error: %+v, expected ItemsError for item 2", err)
+	}
+
+	expected := map[string]tgtg.Price{"1": pln(3000)}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("ItemValues returned: %+v, expected: %+v", values, expected)
+	}
+}
@@ -0,0 +1,177 @@
+// Package stats aggregates savings and impact statistics from Too Good To Go order history.
+//
+//	orders, err := stats.InactiveOrders(ctx, client, 0)
+//	...
+//	values, err := stats.ItemValues(ctx, client, orders)
+//	...
+//	s := stats.Compute(orders, values)
+//	fmt.Println(s.Saved["PLN"], s.MealsSaved, s.CO2Saved)
+//
+// Too Good To Go app shows impact statistics of the account, but the endpoint serving them is not
+// known to this library, therefore impact is estimated from order history instead.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// CO2PerMeal is estimated CO2e, in kilograms, saved with every rescued meal, as communicated by Too Good To Go.
+const CO2PerMeal = 2.5
+
+// StateCancelled is state of cancelled orders.
+const StateCancelled = "CANCELLED"
+
+// StoreStats represents statistics of orders from single store.
+type StoreStats struct {
+	StoreID   string
+	StoreName string
+	Orders    int
+	Meals     int
+
+	// Spent holds total spent per currency code.
+	Spent map[string]tgtg.Price
+}
+
+// MonthStats represents statistics of orders purchased in single month.
+type MonthStats struct {
+	// Month is first day of month, in UTC.
+	Month  time.Time
+	Orders int
+	Meals  int
+
+	// Spent holds total spent per currency code.
+	Spent map[string]tgtg.Price
+}
+
+// Stats represents statistics of order history.
+type Stats struct {
+	// Orders is number of all orders, Cancelled of cancelled ones.
+	Orders    int
+	Cancelled int
+
+	// CancellationRate is share of cancelled orders, from 0 to 1.
+	CancellationRate float64
+
+	// Spent holds total paid for not cancelled orders, per currency code.
+	Spent map[string]tgtg.Price
+
+	// Value holds total original value of not cancelled orders with known item value, per currency code.
+	Value map[string]tgtg.Price
+
+	// Saved holds difference between Value and paid for the same orders, per currency code.
+	Saved map[string]tgtg.Price
+
+	// MealsSaved is number of bags of not cancelled orders, CO2Saved is its estimated CO2e in kilograms.
+	MealsSaved int
+	CO2Saved   float64
+
+	// Stores holds statistics per store, ordered by number of orders, most ordered first.
+	Stores []StoreStats
+
+	// Months holds statistics per month of purchase, oldest first.
+	Months []MonthStats
+}
+
+// Compute aggregates statistics of orders. Values map item IDs to original value of single bag,
+// as returned by ItemValues. Orders of items without known value do not contribute to Value and Saved.
+func Compute(orders []tgtg.Order, values map[string]tgtg.Price) *Stats {
+	s := &Stats{
+		Spent: map[string]tgtg.Price{},
+		Value: map[string]tgtg.Price{},
+		Saved: map[string]tgtg.Price{},
+	}
+	stores := map[string]*StoreStats{}
+	months := map[time.Time]*MonthStats{}
+
+	for _, order := range orders {
+		s.Orders++
+		if order.State == StateCancelled {
+			s.Cancelled++
+			continue
+		}
+
+		paid := order.PriceIncludingTaxes
+		add(s.Spent, paid)
+		s.MealsSaved += order.Quantity
+
+		if value, ok := values[order.ItemID]; ok && value.Code == paid.Code {
+			value.MinorUnits *= order.Quantity
+			if saved, err := value.Sub(paid); err == nil {
+				add(s.Value, value)
+				add(s.Saved, saved)
+			}
+		}
+
+		store, ok := stores[order.StoreID]
+		if !ok {
+			store = &StoreStats{StoreID: order.StoreID, StoreName: order.StoreName, Spent: map[string]tgtg.Price{}}
+			stores[order.StoreID] = store
+		}
+		store.Orders++
+		store.Meals += order.Quantity
+		add(store.Spent, paid)
+
+		purchased := order.TimeOfPurchase.Time
+		if purchased.IsZero() {
+			purchased = order.PickupInterval.Start.Time
+		}
+		purchased = purchased.UTC()
+		month := time.Date(purchased.Year(), purchased.Month(), 1, 0, 0, 0, 0, time.UTC)
+		monthStats, ok := months[month]
+		if !ok {
+			monthStats = &MonthStats{Month: month, Spent: map[string]tgtg.Price{}}
+			months[month] = monthStats
+		}
+		monthStats.Orders++
+		monthStats.Meals += order.Quantity
+		add(monthStats.Spent, paid)
+	}
+
+	if s.Orders > 0 {
+		s.CancellationRate = float64(s.Cancelled) / float64(s.Orders)
+	}
+	s.CO2Saved = float64(s.MealsSaved) * CO2PerMeal
+
+	for _, store := range stores {
+		s.Stores = append(s.Stores, *store)
+	}
+	sort.Slice(s.Stores, func(i, j int) bool {
+		if s.Stores[i].Orders != s.Stores[j].Orders {
+			return s.Stores[i].Orders > s.Stores[j].Orders
+		}
+		return s.Stores[i].StoreName < s.Stores[j].StoreName
+	})
+
+	for _, month := range months {
+		s.Months = append(s.Months, *month)
+	}
+	sort.Slice(s.Months, func(i, j int) bool { return s.Months[i].Month.Before(s.Months[j].Month) })
+
+	return s
+}
+
+// FavoriteStores returns up to n stores with the most orders, none if n is not positive.
+func (s *Stats) FavoriteStores(n int) []StoreStats {
+	if n > len(s.Stores) {
+		n = len(s.Stores)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return s.Stores[:n]
+}
+
+// add adds price to total of its currency.
+func add(totals map[string]tgtg.Price, price tgtg.Price) {
+	total, ok := totals[price.Code]
+	if !ok {
+		totals[price.Code] = price
+		return
+	}
+	if sum, err := total.Add(price); err == nil {
+		totals[price.Code] = sum
+	}
+}
@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func pln(minorUnits int) tgtg.Price {
+	return tgtg.Price{Code: "PLN", MinorUnits: minorUnits, Decimals: 2}
+}
+
+func order(id, state, storeID, itemID string, quantity, paid int, purchased time.Time) tgtg.Order {
+	return tgtg.Order{
+		OrderID:             id,
+		State:               state,
+		StoreID:             storeID,
+		StoreName:           "store-" + storeID,
+		ItemID:              itemID,
+		Quantity:            quantity,
+		PriceIncludingTaxes: pln(paid),
+		TimeOfPurchase:      tgtg.Timestamp{Time: purchased},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	november := time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC)
+	december := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	orders := []tgtg.Order{
+		order("1", "REDEEMED", "s1", "i1", 2, 2000, november),
+		order("2", "REDEEMED", "s2", "i2", 1, 1500, december),
+		order("3", "REDEEMED", "s1", "i1", 1, 1000, december),
+		order("4", StateCancelled, "s2", "i2", 1, 1500, december),
+	}
+	values := map[string]tgtg.Price{"i1": pln(3000)}
+
+	s := Compute(orders, values)
+
+	if s.Orders != 4 || s.Cancelled != 1 || s.CancellationRate != 0.25 {
+		t.Errorf("Compute returned orders: %d, cancelled: %d, rate: %v, expected: 4, 1, 0.25", s.Orders, s.Cancelled, s.CancellationRate)
+	}
+	if s.MealsSaved != 4 || s.CO2Saved != 10 {
+		t.Errorf("Compute returned meals: %d, CO2: %v, expected: 4, 10", s.MealsSaved, s.CO2Saved)
+	}
+
+	expectedTotals := []struct {
+		title    string
+		actual   map[string]tgtg.Price
+		expected tgtg.Price
+	}{
+		{title: "Spent", actual: s.Spent, expected: pln(4500)},
+		{title: "Value", actual: s.Value, expected: pln(9000)},
+		{title: "Saved", actual: s.Saved, expected: pln(6000)},
+	}
+	for _, tc := range expectedTotals {
+		if !reflect.DeepEqual(tc.actual, map[string]tgtg.Price{"PLN": tc.expected}) {
+			t.Errorf("Stats.%s: %+v, expected: %+v", tc.title, tc.actual, tc.expected)
+		}
+	}
+
+	favorite := s.FavoriteStores(1)
+	if len(favorite) != 1 || favorite[0].StoreID != "s1" || favorite[0].Orders != 2 || favorite[0].Meals != 3 {
+		t.Errorf("Stats.FavoriteStores returned: %+v, expected s1 with 2 orders", favorite)
+	}
+	if actual := s.FavoriteStores(10); len(actual) != 2 {
+		t.Errorf("Stats.FavoriteStores returned %d stores, expected: 2", len(actual))
+	}
+
+	var months []string
+	var monthOrders []int
+	for _, month := range s.Months {
+		months = append(months, month.Month.Format("2006-01"))
+		monthOrders = append(monthOrders, month.Orders)
+	}
+	if expected := []string{"2021-11", "2021-12"}; !reflect.DeepEqual(months, expected) {
+		t.Errorf("Stats.Months: %+v, expected: %+v", months, expected)
+	}
+	if expected := []int{1, 2}; !reflect.DeepEqual(monthOrders, expected) {
+		t.Errorf("Stats.Months orders: %+v, expected: %+v", monthOrders, expected)
+	}
+}
+
+func TestStats_FavoriteStores(t *testing.T) {
+	s := &Stats{Stores: []StoreStats{{StoreID: "s1"}, {StoreID: "s2"}}}
+
+	testCases := []struct {
+		title    string
+		n        int
+		expected []string
+	}{
+		{title: "negative", n: -1, expected: nil},
+		{title: "zero", n: 0, expected: nil},
+		{title: "some", n: 1, expected: []string{"s1"}},
+		{title: "more than stores", n: 3, expected: []string{"s1", "s2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			var actual []string
+			for _, store := range s.FavoriteStores(tc.n) {
+				actual = append(actual, store.StoreID)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Stats.FavoriteStores returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCompute_Empty(t *testing.T) {
+	s := Compute(nil, nil)
+	if s.Orders != 0 || s.CancellationRate != 0 || len(s.Stores) != 0 || len(s.FavoriteStores(3)) != 0 {
+		t.Errorf("Compute returned: %+v, expected empty stats", s)
+	}
+}
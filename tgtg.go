@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -44,6 +45,33 @@ type Client struct {
 
 	// Optional extra HTTP headers to set on every request to the Too Good To Go API.
 	headers map[string]string
+
+	// Optional reporter of schema drift in Too Good To Go API responses.
+	schemaReporter SchemaReporter
+
+	// Whether raw JSON responses are attached to schema reports.
+	captureRawJSON bool
+
+	// Codec used for request and response bodies.
+	codec Codec
+
+	// Whether raw response body is kept readable in returned responses.
+	keepRawResponse bool
+
+	// Optional dumper of requests and responses.
+	debug *debugDumper
+
+	// Optional middleware wrapping every request.
+	middleware []Middleware
+
+	// Optional structured logger.
+	logger Logger
+
+	// Optional limiter of requests rate.
+	limiter RateLimiter
+
+	// Optional cache of responses, shared by clones.
+	cache *responseCache
 }
 
 // NewClient returns a new Too Good To Go API client.
@@ -57,6 +85,7 @@ func NewClient(httpClient *http.Client) *Client {
 		client:    httpClient,
 		BaseURL:   baseURL,
 		UserAgent: defaultUserAgent,
+		codec:     jsonCodec{},
 	}
 	c.Auth = &AuthServiceOp{client: c}
 	c.Items = &ItemsServiceOp{client: c}
@@ -101,6 +130,36 @@ func SetRequestHeaders(headers map[string]string) ClientOption {
 	}
 }
 
+// Clone returns a copy of the client sharing its HTTP client, options and auth context.
+// Changes to the auth context of the copy, e.g. by Auth service, do not affect the original client.
+func (c *Client) Clone() *Client {
+	clone := *c
+	clone.Auth = &AuthServiceOp{client: &clone}
+	clone.Items = &ItemsServiceOp{client: &clone}
+	clone.Orders = &OrdersServiceOp{client: &clone}
+
+	clone.headers = make(map[string]string, len(c.headers))
+	for k, v := range c.headers {
+		clone.headers[k] = v
+	}
+	clone.middleware = append([]Middleware(nil), c.middleware...)
+
+	if c.BaseURL != nil {
+		baseURL := *c.BaseURL
+		clone.BaseURL = &baseURL
+	}
+
+	return &clone
+}
+
+// WithAuth returns a copy of the client, see Clone, with given auth context: access token, refresh token and user id.
+// It allows calling Too Good To Go API on behalf of different users with single configured client.
+func (c *Client) WithAuth(accessToken, refreshToken, userID string) *Client {
+	clone := c.Clone()
+	clone.SetAuthContext(accessToken, refreshToken, userID)
+	return clone
+}
+
 // SetAuthContext sets Too Good To Go API auth context: access token, refresh token and user id.
 func (c *Client) SetAuthContext(accessToken, refreshToken, userID string) {
 	c.AccessToken = accessToken
@@ -110,7 +169,7 @@ func (c *Client) SetAuthContext(accessToken, refreshToken, userID string) {
 
 // NewRequest creates Too Good To Go API request. Relative URL has to be provided in url, which will be merged with
 // BaseURL of the Client. URL has to start with no "/" prefix, only relative URL is handled. If specified, the value pointing at body would be
-// JSON encoded and included in as the request body.
+// encoded with client's Codec (JSON by default) and included in as the request body.
 func (c *Client) NewRequest(method, url string, body interface{}) (*http.Request, error) {
 	u, err := c.BaseURL.Parse(url)
 	if err != nil {
@@ -125,15 +184,15 @@ func (c *Client) NewRequest(method, url string, body interface{}) (*http.Request
 			return nil, err
 		}
 	default:
-		buf := &bytes.Buffer{}
+		var data []byte
 		if body != nil {
-			err = json.NewEncoder(buf).Encode(body)
+			data, err = c.codec.Marshal(body)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		request, err = http.NewRequest(method, u.String(), buf)
+		request, err = http.NewRequest(method, u.String(), bytes.NewReader(data))
 		if err != nil {
 			return nil, err
 		}
@@ -151,30 +210,76 @@ func (c *Client) NewRequest(method, url string, body interface{}) (*http.Request
 	return request, nil
 }
 
-// Do sends Too Good To Go API request and returns response. The response is JSON decoded
-// and stored in the value pointed to by v, or returned as an error if occurred.
+// Do sends Too Good To Go API request and returns response. The response is decoded with client's Codec
+// (JSON by default) and stored in the value pointed to by v, or returned as an error if occurred.
+// Empty response body cannot be decoded into v, io.EOF is returned then.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
-	response, err := c.doRequest(ctx, req)
+	ctx = withEndpoint(withAttempts(ctx), c.endpoint(req))
+	response, err := c.handler()(ctx, ServiceMethod(ctx), req)
 	if err != nil {
 		return nil, err
 	}
 
-	err = CheckResponseForErrors(response)
+	data, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
 	if err != nil {
 		return nil, err
 	}
 
+	// Body has been read already, readable copy of it is returned only if requested.
+	response.Body = http.NoBody
+	if c.keepRawResponse {
+		response.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
 	if v != nil {
-		// fmt.Println("123")
-		// x, _ := io.ReadAll(response.Body)
-		// fmt.Println(string(x))
-		err = json.NewDecoder(response.Body).Decode(v)
+		if len(bytes.TrimSpace(data)) == 0 {
+			return nil, io.EOF
+		}
+
+		err = c.codec.Unmarshal(data, v)
 		if err != nil {
 			return nil, err
 		}
+
+		if c.schemaReporter != nil {
+			c.reportSchema(req, data, v)
+		}
+	}
+
+	return response, nil
+}
+
+// send is the innermost Handler. It sends the request, buffers response body and checks response for errors.
+func (c *Client) send(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+	addAttempt(ctx)
+	if c.debug != nil {
+		c.debug.dumpRequest(req)
+	}
+
+	response, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	if c.debug != nil {
+		c.debug.dumpResponse(req, response, data)
+	}
+
+	err = CheckResponseForErrors(response)
+	if err != nil {
+		return response, err
 	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(data))
 
-	return response, err
+	return response, nil
 }
 
 func (c *Client) doRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
@@ -211,3 +211,52 @@ func testMethod(t *testing.T, r *http.Request, expected string) {
 		t.Fatalf("Request method: %+v, expected: %+v", r.Method, expected)
 	}
 }
+
+func TestClient_WithAuth(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.SetAuthContext("access-1", "refresh-1", "1")
+	client.headers["X-Custom"] = "custom"
+
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		if actual := r.Header.Get("Authorization"); actual != "Bearer access-2" {
+			t.Errorf("Authorization header: %+v, expected: Bearer access-2", actual)
+		}
+		if actual := r.Header.Get("X-Custom"); actual != "custom" {
+			t.Errorf("X-Custom header: %+v, expected: custom", actual)
+		}
+		fmt.Fprint(w, `{"orders": []}`)
+	})
+	mux.HandleFunc("/auth/v3/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "access-3", "refresh_token": "refresh-3"}`)
+	})
+
+	other := client.WithAuth("access-2", "refresh-2", "2")
+	if other.BaseURL.String() != client.BaseURL.String() {
+		t.Errorf("WithAuth base url: %+v, expected: %+v", other.BaseURL, client.BaseURL)
+	}
+
+	_, _, err := other.Orders.Active(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Orders.Active returned error: %+v", err)
+	}
+
+	_, _, err = other.Auth.Refresh(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Auth.Refresh returned error: %+v", err)
+	}
+
+	if other.AccessToken != "access-3" || other.UserID != "2" {
+		t.Errorf("WithAuth client: %+v, expected refreshed access-3 token and user 2", other)
+	}
+
+	if client.AccessToken != "access-1" || client.RefreshToken != "refresh-1" || client.UserID != "1" {
+		t.Errorf("Original client: %+v, expected unchanged auth context", client)
+	}
+
+	other.headers["X-Custom"] = "changed"
+	if client.headers["X-Custom"] != "custom" {
+		t.Errorf("Original client headers: %+v, expected unchanged", client.headers)
+	}
+}
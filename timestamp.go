@@ -0,0 +1,73 @@
+package tgtg
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// timestampLayouts lists layouts of time values returned by Too Good To Go API, tried in order.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Timestamp represents a time value returned by Too Good To Go API. It is tolerant to the API quirks:
+// null, empty and unparsable values decode into zero Timestamp instead of failing the whole response,
+// and multiple layouts as well as Unix seconds are accepted. Check IsZero before using the value.
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface's method.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	t.Time = time.Time{}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if data[0] != '"' {
+		if seconds, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+			t.Time = time.Unix(seconds, 0).UTC()
+		}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+
+	t.Time = parseTimestamp(value)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler interface's method. Zero Timestamp is encoded as null.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
+
+// parseTimestamp parses value using known layouts, returning zero time if none matches.
+func parseTimestamp(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,87 @@
+package tgtg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		title    string
+		data     string
+		expected time.Time
+	}{
+		{title: "RFC3339", data: `"2021-12-01T18:00:00Z"`, expected: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+		{title: "RFC3339 with offset", data: `"2021-12-01T19:00:00+01:00"`, expected: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+		{title: "fractional seconds", data: `"2021-12-01T18:00:00.5Z"`, expected: time.Date(2021, 12, 1, 18, 0, 0, 5e8, time.UTC)},
+		{title: "no zone", data: `"2021-12-01T18:00:00"`, expected: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+		{title: "space separated", data: `"2021-12-01 18:00:00"`, expected: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+		{title: "date only", data: `"2021-12-01"`, expected: time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{title: "unix seconds", data: `1638381600`, expected: time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+		{title: "empty string", data: `""`},
+		{title: "null", data: `null`},
+		{title: "garbage", data: `"not a time"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			actual := Timestamp{time.Now()}
+			if err := json.Unmarshal([]byte(tc.data), &actual); err != nil {
+				t.Fatalf("Timestamp.UnmarshalJSON returned error: %+v", err)
+			}
+
+			if !actual.Equal(tc.expected) {
+				t.Errorf("Timestamp.UnmarshalJSON returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Set   Timestamp `json:"set"`
+		Unset Timestamp `json:"unset"`
+	}{
+		Set: Timestamp{time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Timestamp.MarshalJSON returned error: %+v", err)
+	}
+
+	expected := `{"set":"2021-12-01T18:00:00Z","unset":null}`
+	if string(data) != expected {
+		t.Errorf("Timestamp.MarshalJSON returned: %+v, expected: %+v", string(data), expected)
+	}
+}
+
+func TestTimestamp_ListItemsResponse(t *testing.T) {
+	data := `
+	{
+		"items": [
+			{
+				"display_name": "name-1",
+				"purchase_end": "",
+				"sold_out_at": null,
+				"pickup_interval": {
+					"start": "2021-12-01T18:00:00Z",
+					"end": "unexpected"
+				}
+			}
+		]
+	}`
+
+	response := &ListItemsResponse{}
+	if err := json.Unmarshal([]byte(data), response); err != nil {
+		t.Fatalf("Decode json: %+v", err)
+	}
+
+	items := response.Items[0]
+	if !items.PurchaseEnd.IsZero() || !items.SoldOutAt.IsZero() || !items.PickupInterval.End.IsZero() {
+		t.Errorf("Items: %+v, expected zero PurchaseEnd, SoldOutAt and PickupInterval.End", items)
+	}
+
+	if expected := time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC); !items.PickupInterval.Start.Equal(expected) {
+		t.Errorf("Items.PickupInterval.Start: %+v, expected: %+v", items.PickupInterval.Start, expected)
+	}
+}
@@ -0,0 +1,80 @@
+// Package tracing integrates Too Good To Go API client with OpenTelemetry tracing.
+//
+// Every service method call (e.g. Items.List, Orders.Active) is recorded as a client span,
+// child of the span carried by the context passed to the call:
+//
+//	client, err := tgtg.New(nil, tgtg.SetMiddleware(tracing.Middleware(otel.GetTracerProvider())))
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/filippalach/tgt-go"
+
+// Span attribute keys.
+const (
+	EndpointKey   = attribute.Key("tgtg.endpoint")
+	ItemIDKey     = attribute.Key("tgtg.item_id")
+	AttemptsKey   = attribute.Key("tgtg.attempts")
+	MethodKey     = attribute.Key("http.method")
+	StatusCodeKey = attribute.Key("http.status_code")
+)
+
+// Middleware returns tgtg.Middleware starting a span per request using tracer from provider.
+// Span is named after service method, or "Client.Do" for requests sent directly with Client.Do.
+// Context carrying the span is propagated to the rest of the chain and the HTTP client.
+func Middleware(provider trace.TracerProvider) tgtg.Middleware {
+	tracer := provider.Tracer(instrumentationName)
+
+	return func(next tgtg.Handler) tgtg.Handler {
+		return func(ctx context.Context, method string, req *http.Request) (*http.Response, error) {
+			name := method
+			if name == "" {
+				name = "Client.Do"
+			}
+
+			endpoint := tgtg.Endpoint(ctx)
+
+			attributes := []attribute.KeyValue{
+				EndpointKey.String(endpoint),
+				MethodKey.String(req.Method),
+			}
+			if itemID := itemID(endpoint); itemID != "" {
+				attributes = append(attributes, ItemIDKey.String(itemID))
+			}
+
+			ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+			defer span.End()
+
+			response, err := next(ctx, method, req)
+
+			span.SetAttributes(AttemptsKey.Int(tgtg.Attempts(ctx)))
+			if response != nil {
+				span.SetAttributes(StatusCodeKey.Int(response.StatusCode))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return response, err
+		}
+	}
+}
+
+// itemID returns item ID from item endpoint, e.g. "item/v7/123/setFavorite", or empty string.
+func itemID(endpoint string) string {
+	parts := strings.Split(endpoint, "/")
+	if len(parts) >= 3 && parts[0] == "item" {
+		return parts[2]
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	tgtg "github.com/filippalach/tgt-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client, err := tgtg.New(nil, tgtg.SetMiddleware(Middleware(provider)))
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.SetAuthContext("access_token", "refresh_token", "1")
+
+	mux.HandleFunc("/item/v7/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"item": {"item_id": "123"}}`)
+	})
+	mux.HandleFunc("/order/v6/active", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	_, _, err = client.Items.Get(ctx, &tgtg.GetItemRequest{}, "123")
+	if err != nil {
+		t.Fatalf("Items.Get returned error: %+v", err)
+	}
+	_, _, err = client.Orders.Active(ctx, nil)
+	if err == nil {
+		t.Fatal("Orders.Active returned no error.")
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Exported spans: %d, expected: 3", len(spans))
+	}
+
+	testCases := []struct {
+		name       string
+		attributes []attribute.KeyValue
+		status     codes.Code
+	}{
+		{
+			name: "Items.Get",
+			attributes: []attribute.KeyValue{
+				EndpointKey.String("item/v7/123"),
+				MethodKey.String(http.MethodPost),
+				ItemIDKey.String("123"),
+				AttemptsKey.Int(1),
+				StatusCodeKey.Int(http.StatusOK),
+			},
+			status: codes.Unset,
+		},
+		{
+			name: "Orders.Active",
+			attributes: []attribute.KeyValue{
+				EndpointKey.String("order/v6/active"),
+				MethodKey.String(http.MethodPost),
+				AttemptsKey.Int(1),
+				StatusCodeKey.Int(http.StatusInternalServerError),
+			},
+			status: codes.Error,
+		},
+	}
+
+	for i, tc := range testCases {
+		span := spans[i]
+		if span.Name != tc.name {
+			t.Errorf("Span name: %+v, expected: %+v", span.Name, tc.name)
+		}
+		if span.SpanKind != trace.SpanKindClient {
+			t.Errorf("Span %s kind: %+v, expected: client", span.Name, span.SpanKind)
+		}
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Span %s parent: %+v, expected: %+v", span.Name, span.Parent.SpanID(), parent.SpanContext().SpanID())
+		}
+		if span.Status.Code != tc.status {
+			t.Errorf("Span %s status: %+v, expected: %+v", span.Name, span.Status.Code, tc.status)
+		}
+
+		actual := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes {
+			actual[kv.Key] = kv.Value
+		}
+		for _, kv := range tc.attributes {
+			if actual[kv.Key] != kv.Value {
+				t.Errorf("Span %s attribute %s: %+v, expected: %+v", span.Name, kv.Key, actual[kv.Key].Emit(), kv.Value.Emit())
+			}
+		}
+		if _, ok := actual[ItemIDKey]; ok && tc.name != "Items.Get" {
+			t.Errorf("Span %s has unexpected %s attribute", span.Name, ItemIDKey)
+		}
+	}
+}
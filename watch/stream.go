@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// keepAliveInterval is how often idle streams are sent keep-alive messages.
+var keepAliveInterval = 15 * time.Second
+
+// Handler returns http.Handler streaming watcher events to every connected client, as Server-Sent Events,
+// or as WebSocket text messages with JSON encoded events if client requests WebSocket upgrade.
+// Events can be filtered with "item_id" and "store_id" query parameters, repeated or comma separated.
+// Last known state of matching items is sent first, as snapshot events.
+func (w *Watcher) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			rw.Header().Set("Allow", http.MethodGet)
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		filter := Filter{
+			ItemIDs:  queryValues(r, "item_id"),
+			StoreIDs: queryValues(r, "store_id"),
+		}
+
+		if isWebSocketUpgrade(r) {
+			w.serveWebSocket(rw, r, filter)
+			return
+		}
+		w.serveSSE(rw, r, filter)
+	})
+}
+
+// serveSSE streams events as Server-Sent Events.
+func (w *Watcher) serveSSE(rw http.ResponseWriter, r *http.Request, filter Filter) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	subscription := w.Subscribe(filter, 0)
+	defer subscription.Close()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(rw, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(rw, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// serveWebSocket streams events as WebSocket text messages.
+func (w *Watcher) serveWebSocket(rw http.ResponseWriter, r *http.Request, filter Filter) {
+	conn, err := upgradeWebSocket(rw, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	subscription := w.Subscribe(filter, 0)
+	defer subscription.Close()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-conn.closed:
+			return
+		case <-keepAlive.C:
+			if err := conn.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events():
+			if !ok {
+				conn.writeFrame(opClose, closePayload(closeGoingAway))
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			if err := conn.writeFrame(opText, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// queryValues returns values of query parameter, repeated or comma separated.
+func queryValues(r *http.Request, name string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,167 @@
+package watch
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// readSSE reads single Server-Sent Event, returning its fields.
+func readSSE(t *testing.T, reader *bufio.Reader) map[string]string {
+	fields := map[string]string{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Read event: %+v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return fields
+		}
+		parts := strings.SplitN(line, ": ", 2)
+		fields[parts[0]] = parts[1]
+	}
+}
+
+func TestWatcher_Handler_SSE(t *testing.T) {
+	w, u := setup(t)
+	u.set(map[string]int{"1": 1, "2": 2})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: %+v", err)
+	}
+
+	server := httptest.NewServer(w.Handler())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "?item_id=2")
+	if err != nil {
+		t.Fatalf("GET returned error: %+v", err)
+	}
+	defer response.Body.Close()
+
+	if actual := response.Header.Get("Content-Type"); actual != "text/event-stream" {
+		t.Errorf("Content-Type: %+v, expected: text/event-stream", actual)
+	}
+
+	reader := bufio.NewReader(response.Body)
+	snapshot := readSSE(t, reader)
+	if snapshot["event"] != "snapshot" || snapshot["id"] != "2" {
+		t.Errorf("Received: %+v, expected snapshot event with id 2", snapshot)
+	}
+
+	u.set(map[string]int{"1": 0, "2": 0})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: %+v", err)
+	}
+
+	change := readSSE(t, reader)
+	var event Event
+	if err := json.Unmarshal([]byte(change["data"]), &event); err != nil {
+		t.Fatalf("Decode json: %+v", err)
+	}
+	if change["event"] != "change" || event.ItemID != "2" || event.Previous != 2 || event.Available != 0 {
+		t.Errorf("Received: %+v, expected change of item 2", change)
+	}
+}
+
+func TestWatcher_Handler_WebSocket(t *testing.T) {
+	w, u := setup(t)
+	u.set(map[string]int{"1": 1})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: %+v", err)
+	}
+
+	server := httptest.NewServer(w.Handler())
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Dial returned error: %+v", err)
+	}
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "GET /?store_id=s1 HTTP/1.1\r\n"+
+		"Host: localhost\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	if err != nil {
+		t.Fatalf("Write handshake: %+v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Read handshake: %+v", err)
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Status: %d, expected: %d", response.StatusCode, http.StatusSwitchingProtocols)
+	}
+	// Example from RFC 6455.
+	if actual := response.Header.Get("Sec-WebSocket-Accept"); actual != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept: %+v, expected: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", actual)
+	}
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("Read frame: %+v", err)
+	}
+	if header[0] != 0x80|opText || header[1]&0x80 != 0 {
+		t.Fatalf("Frame header: %x, expected final unmasked text frame", header)
+	}
+	length := int(header[1])
+	if length == 126 {
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			t.Fatalf("Read frame: %+v", err)
+		}
+		length = int(binary.BigEndian.Uint16(extended))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("Read frame: %+v", err)
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Decode json: %+v", err)
+	}
+	if event.Type != EventSnapshot || event.ItemID != "1" || event.Available != 1 {
+		t.Errorf("Received: %+v, expected snapshot of item 1", event)
+	}
+
+	// Masked close frame with status code 1000.
+	mask := []byte{1, 2, 3, 4}
+	code := make([]byte, 2)
+	binary.BigEndian.PutUint16(code, 1000)
+	frame := append([]byte{0x80 | opClose, 0x80 | 2}, mask...)
+	frame = append(frame, code[0]^mask[0], code[1]^mask[1])
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("Write close frame: %+v", err)
+	}
+
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("Read frame: %+v", err)
+	}
+	if header[0] != 0x80|opClose {
+		t.Errorf("Frame header: %x, expected close frame", header)
+	}
+}
+
+func TestWatcher_Handler_MethodNotAllowed(t *testing.T) {
+	w, _ := setup(t)
+
+	recorder := httptest.NewRecorder()
+	w.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Status: %d, expected: %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,309 @@
+// Package watch polls Too Good To Go items and notifies subscribers about changes of their availability.
+//
+//	w := watch.New(client, request, watch.Every(time.Minute))
+//	subscription := w.Subscribe(watch.Filter{StoreIDs: []string{storeID}}, 0)
+//	go w.Run(ctx, func(err error) { log.Printf("polling failed: %v", err) })
+//	for event := range subscription.Events() {
+//		...
+//	}
+//
+// Watcher can be also served to web clients as Server-Sent Events or WebSocket stream, see Handler.
+package watch
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+const (
+	defaultBuffer = 16
+
+	// MinInterval is the shortest wait between polls. Shorter intervals returned by Schedule are clamped to it.
+	MinInterval = time.Second
+)
+
+// Schedule decides how long to wait between polls. forecast.Schedule implements Schedule.
+type Schedule interface {
+	Interval(now time.Time) time.Duration
+}
+
+// Every returns Schedule polling at fixed interval.
+func Every(interval time.Duration) Schedule {
+	return fixedSchedule(interval)
+}
+
+type fixedSchedule time.Duration
+
+func (s fixedSchedule) Interval(time.Time) time.Duration {
+	return time.Duration(s)
+}
+
+// EventType describes kind of Event.
+type EventType string
+
+const (
+	// EventSnapshot carries last known state of item, replayed to new subscribers.
+	EventSnapshot EventType = "snapshot"
+
+	// EventChange reports change of item availability.
+	EventChange EventType = "change"
+)
+
+// Event represents availability of single item.
+type Event struct {
+	// ID is sequence number of the event, increasing with every change. Snapshot events carry ID of latest change.
+	ID   uint64    `json:"id"`
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	ItemID      string `json:"item_id"`
+	StoreID     string `json:"store_id"`
+	StoreName   string `json:"store_name"`
+	DisplayName string `json:"display_name"`
+
+	// Previous is number of bags available before the change, zero for snapshots and new items.
+	Previous  int `json:"previous"`
+	Available int `json:"available"`
+}
+
+// Filter selects events of given items or stores. Empty filter matches all events.
+type Filter struct {
+	ItemIDs  []string
+	StoreIDs []string
+}
+
+// Match reports whether event matches filter: its item or its store is selected.
+func (f Filter) Match(event Event) bool {
+	if len(f.ItemIDs) == 0 && len(f.StoreIDs) == 0 {
+		return true
+	}
+	for _, id := range f.ItemIDs {
+		if id == event.ItemID {
+			return true
+		}
+	}
+	for _, id := range f.StoreIDs {
+		if id == event.StoreID {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives events matching its filter.
+type Subscription struct {
+	watcher *Watcher
+	filter  Filter
+	events  chan Event
+	closed  bool
+}
+
+// Events returns channel of events. It is closed when subscription is closed, either by Close
+// or by watcher, if subscriber does not keep up with events.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops delivery of events and closes events channel.
+func (s *Subscription) Close() {
+	s.watcher.mu.Lock()
+	defer s.watcher.mu.Unlock()
+	s.watcher.unsubscribe(s)
+}
+
+// Watcher polls items listed by request and publishes changes of their availability. It is safe for concurrent use.
+type Watcher struct {
+	client   *tgtg.Client
+	request  *tgtg.ListItemsRequest
+	schedule Schedule
+	now      func() time.Time
+
+	mu          sync.Mutex
+	sequence    uint64
+	state       map[string]Event
+	subscribers map[*Subscription]struct{}
+}
+
+// New returns Watcher polling items listed by request according to schedule.
+func New(client *tgtg.Client, request *tgtg.ListItemsRequest, schedule Schedule) *Watcher {
+	return &Watcher{
+		client:      client,
+		request:     request,
+		schedule:    schedule,
+		now:         time.Now,
+		state:       map[string]Event{},
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Run polls items until ctx is done. Failed polls are retried according to schedule, errors are passed to onError, if not nil.
+// Polls are at least MinInterval apart, whatever schedule returns.
+func (w *Watcher) Run(ctx context.Context, onError func(error)) error {
+	for {
+		if _, err := w.Poll(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+
+		interval := w.schedule.Interval(w.now())
+		if interval < MinInterval {
+			interval = MinInterval
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll lists items once, publishes and returns changes of their availability. Items no longer listed are reported
+// as unavailable. First poll reports all listed items as changed.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	response, _, err := w.client.Items.List(ctx, w.request)
+	if err != nil {
+		return nil, err
+	}
+	return w.update(response.Items), nil
+}
+
+// update applies listed items to watcher state and publishes changes.
+func (w *Watcher) update(items []tgtg.Items) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	var changes []Event
+	listed := make(map[string]bool, len(items))
+
+	for _, entry := range items {
+		itemID := entry.Item.ItemID
+		listed[itemID] = true
+
+		previous, known := w.state[itemID]
+		if known && previous.Available == entry.ItemsAvailable {
+			continue
+		}
+
+		w.sequence++
+		event := Event{
+			ID:          w.sequence,
+			Type:        EventChange,
+			Time:        now,
+			ItemID:      itemID,
+			StoreID:     entry.Store.StoreID,
+			StoreName:   entry.Store.StoreName,
+			DisplayName: entry.DisplayName,
+			Previous:    previous.Available,
+			Available:   entry.ItemsAvailable,
+		}
+		w.state[itemID] = event
+		changes = append(changes, event)
+	}
+
+	var unlisted []string
+	for itemID := range w.state {
+		if !listed[itemID] {
+			unlisted = append(unlisted, itemID)
+		}
+	}
+	sort.Strings(unlisted)
+
+	for _, itemID := range unlisted {
+		previous := w.state[itemID]
+		delete(w.state, itemID)
+		if previous.Available == 0 {
+			continue
+		}
+
+		w.sequence++
+		event := previous
+		event.ID = w.sequence
+		event.Time = now
+		event.Previous = previous.Available
+		event.Available = 0
+		changes = append(changes, event)
+	}
+
+	for _, event := range changes {
+		w.publish(event)
+	}
+	return changes
+}
+
+// publish sends event to matching subscribers. Subscribers with full buffer are closed,
+// so that they can subscribe again and receive current state instead of missing changes.
+func (w *Watcher) publish(event Event) {
+	for subscription := range w.subscribers {
+		if !subscription.filter.Match(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			w.unsubscribe(subscription)
+		}
+	}
+}
+
+// Subscribe returns Subscription of events matching filter. Last known state of matching items is replayed
+// first, as snapshot events. Buffer specifies how many events can be queued, 16 if not positive; it is extended
+// to fit the replay.
+func (w *Watcher) Subscribe(filter Filter, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	snapshot := w.snapshot(filter)
+	subscription := &Subscription{
+		watcher: w,
+		filter:  filter,
+		events:  make(chan Event, buffer+len(snapshot)),
+	}
+	for _, event := range snapshot {
+		subscription.events <- event
+	}
+
+	w.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// State returns last known state of items matching filter, as snapshot events ordered by item ID.
+func (w *Watcher) State(filter Filter) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.snapshot(filter)
+}
+
+func (w *Watcher) snapshot(filter Filter) []Event {
+	events := make([]Event, 0, len(w.state))
+	for _, event := range w.state {
+		if !filter.Match(event) {
+			continue
+		}
+		event.Type = EventSnapshot
+		event.ID = w.sequence
+		event.Previous = 0
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ItemID < events[j].ItemID })
+	return events
+}
+
+func (w *Watcher) unsubscribe(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	delete(w.subscribers, subscription)
+	close(subscription.events)
+}
@@ -0,0 +1,217 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// upstream serves Items.List responses with availability set by tests.
+type upstream struct {
+	mu        sync.Mutex
+	available map[string]int
+	polls     int
+}
+
+func (u *upstream) set(available map[string]int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.available = available
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.polls++
+
+	fmt.Fprint(w, `{"items": [`)
+	first := true
+	for _, itemID := range []string{"1", "2", "3"} {
+		available, ok := u.available[itemID]
+		if !ok {
+			continue
+		}
+		if !first {
+			fmt.Fprint(w, ",")
+		}
+		first = false
+		fmt.Fprintf(w, `{"item": {"item_id": %q}, "store": {"store_id": "s%s"}, "items_available": %d}`, itemID, itemID, available)
+	}
+	fmt.Fprint(w, `]}`)
+}
+
+func setup(t *testing.T) (*Watcher, *upstream) {
+	u := &upstream{}
+	server := httptest.NewServer(u)
+	t.Cleanup(server.Close)
+
+	client := tgtg.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	request := &tgtg.ListItemsRequest{UserID: "1", Origin: &tgtg.Origin{}}
+	w := New(client, request, Every(time.Millisecond))
+	w.now = func() time.Time { return time.Date(2021, 12, 1, 18, 0, 0, 0, time.UTC) }
+	return w, u
+}
+
+// changes returns events as "itemID:previous->available" strings.
+func changes(events []Event) []string {
+	var actual []string
+	for _, event := range events {
+		actual = append(actual, fmt.Sprintf("%s:%s:%d->%d", event.Type, event.ItemID, event.Previous, event.Available))
+	}
+	return actual
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	w, u := setup(t)
+
+	testCases := []struct {
+		title     string
+		available map[string]int
+		expected  []string
+	}{
+		{title: "first poll", available: map[string]int{"1": 0, "2": 3}, expected: []string{"change:1:0->0", "change:2:0->3"}},
+		{title: "no changes", available: map[string]int{"1": 0, "2": 3}, expected: nil},
+		{title: "changed", available: map[string]int{"1": 2, "2": 1}, expected: []string{"change:1:0->2", "change:2:3->1"}},
+		{title: "unlisted", available: map[string]int{"2": 1}, expected: []string{"change:1:2->0"}},
+		{title: "listed again", available: map[string]int{"1": 0, "2": 1}, expected: []string{"change:1:0->0"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			u.set(tc.available)
+
+			events, err := w.Poll(context.Background())
+			if err != nil {
+				t.Fatalf("Watcher.Poll returned error: %+v", err)
+			}
+			if actual := changes(events); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Watcher.Poll returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWatcher_Subscribe(t *testing.T) {
+	w, u := setup(t)
+
+	u.set(map[string]int{"1": 1, "2": 2, "3": 3})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: %+v", err)
+	}
+
+	subscription := w.Subscribe(Filter{ItemIDs: []string{"1"}, StoreIDs: []string{"s3"}}, 0)
+
+	u.set(map[string]int{"1": 0, "2": 0, "3": 3})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: %+v", err)
+	}
+	subscription.Close()
+
+	var events []Event
+	for event := range subscription.Events() {
+		events = append(events, event)
+	}
+
+	expected := []string{"snapshot:1:0->1", "snapshot:3:0->3", "change:1:1->0"}
+	if actual := changes(events); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Subscription received: %+v, expected: %+v", actual, expected)
+	}
+	if events[0].ID != 3 || events[2].ID != 4 {
+		t.Errorf("Subscription received IDs: %d, %d, expected: 3, 4", events[0].ID, events[2].ID)
+	}
+
+	// Closing twice is a no-op.
+	subscription.Close()
+}
+
+func TestWatcher_SlowSubscriber(t *testing.T) {
+	w, u := setup(t)
+	subscription := w.Subscribe(Filter{}, 1)
+
+	for i := 1; i <= 3; i++ {
+		u.set(map[string]int{"1": i})
+		if _, err := w.Poll(context.Background()); err != nil {
+			t.Fatalf("Watcher.Poll returned error: %+v", err)
+		}
+	}
+
+	var received int
+	for range subscription.Events() {
+		received++
+	}
+	if received != 1 {
+		t.Errorf("Subscription received %d events, expected: 1 before being closed", received)
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	w, u := setup(t)
+	u.set(map[string]int{"1": 1})
+
+	subscription := w.Subscribe(Filter{}, 0)
+	defer subscription.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx, nil) }()
+
+	event := <-subscription.Events()
+	if event.ItemID != "1" || event.Available != 1 {
+		t.Errorf("Subscription received: %+v, expected availability of item 1", event)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watcher.Run returned: %+v, expected: %+v", err, context.Canceled)
+	}
+}
+
+func TestWatcher_RunNonPositiveInterval(t *testing.T) {
+	w, u := setup(t)
+	w.schedule = Every(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := w.Run(ctx, nil); err != context.DeadlineExceeded {
+		t.Errorf("Watcher.Run returned: %+v, expected: %+v", err, context.DeadlineExceeded)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.polls != 1 {
+		t.Errorf("Watcher.Run polled %d times, expected: 1", u.polls)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	event := Event{ItemID: "1", StoreID: "s1"}
+
+	testCases := []struct {
+		title    string
+		filter   Filter
+		expected bool
+	}{
+		{title: "empty", filter: Filter{}, expected: true},
+		{title: "item", filter: Filter{ItemIDs: []string{"2", "1"}}, expected: true},
+		{title: "store", filter: Filter{StoreIDs: []string{"s1"}}, expected: true},
+		{title: "other", filter: Filter{ItemIDs: []string{"2"}, StoreIDs: []string{"s2"}}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := tc.filter.Match(event); actual != tc.expected {
+				t.Errorf("Filter.Match returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package watch
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Minimal server side of WebSocket protocol (RFC 6455), sufficient to stream messages to clients.
+// Messages received from clients are discarded, apart from close and ping frames.
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+
+	closeGoingAway = 1001
+)
+
+// maxControlPayload is maximum payload length of control frames.
+const maxControlPayload = 125
+
+// isWebSocketUpgrade reports whether request asks for WebSocket protocol.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return headerContains(r.Header, "Connection", "upgrade") && headerContains(r.Header, "Upgrade", "websocket")
+}
+
+// headerContains reports whether comma separated header values contain token, case-insensitively.
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// websocketConn is a server side WebSocket connection.
+type websocketConn struct {
+	conn net.Conn
+
+	mu sync.Mutex
+	rw *bufio.ReadWriter
+
+	// closed is closed once client closes connection or reading from it fails.
+	closed chan struct{}
+}
+
+// upgradeWebSocket completes WebSocket handshake and starts reading client frames.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*websocketConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("watch: unsupported WebSocket handshake")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("watch: response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	_, err = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n")
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &websocketConn{conn: conn, rw: rw, closed: make(chan struct{})}
+	go c.readFrames()
+	return c, nil
+}
+
+// writeFrame writes single unfragmented, unmasked frame.
+func (c *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length <= 125:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// readFrames reads client frames until connection is closed, answering pings and close frames.
+func (c *websocketConn) readFrames() {
+	defer close(c.closed)
+
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFrame reads single client frame. Payloads of data frames are discarded.
+func (c *websocketConn) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.rw, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.rw, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.rw, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	// Clients must mask their frames.
+	if !masked {
+		return 0, nil, errors.New("watch: unmasked client frame")
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	if opcode < opClose {
+		_, err := io.CopyN(io.Discard, c.rw, int64(length))
+		return opcode, nil, err
+	}
+	if length > maxControlPayload {
+		return 0, nil, errors.New("watch: control frame too long")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// Close closes underlying connection.
+func (c *websocketConn) Close() error {
+	return c.conn.Close()
+}
+
+// closePayload returns payload of close frame with status code.
+func closePayload(code uint16) []byte {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, code)
+	return payload
+}
@@ -0,0 +1,631 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	deliveriesBucket = []byte("deliveries")
+
+	// dueBucket indexes pending deliveries by time of the next attempt.
+	dueBucket = []byte("due")
+
+	// retriesBucket holds time of the latest scheduled retry by endpoint URL. Deliveries enqueued
+	// before it are held back until then, so that they do not overtake the retried one.
+	retriesBucket = []byte("retries")
+)
+
+// Status is state of a delivery.
+type Status string
+
+// Delivery statuses.
+const (
+	// StatusPending deliveries wait for the next attempt.
+	StatusPending Status = "pending"
+
+	// StatusDelivered deliveries were accepted by endpoint with 2xx response.
+	StatusDelivered Status = "delivered"
+
+	// StatusFailed deliveries were rejected by endpoint, or ran out of attempts. See Redeliver.
+	StatusFailed Status = "failed"
+)
+
+// Endpoint is a receiver of events.
+type Endpoint struct {
+	// URL events are POSTed to.
+	URL string
+
+	// Secret used to sign requests.
+	Secret string
+}
+
+// Delivery represents event to be delivered to single endpoint, along with its delivery status.
+type Delivery struct {
+	ID       uint64 `json:"id"`
+	Endpoint string `json:"endpoint"`
+	Event    Event  `json:"event"`
+
+	Status      Status    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	CreatedAt   time.Time `json:"created_at"`
+	NextAttempt time.Time `json:"next_attempt"`
+	CompletedAt time.Time `json:"completed_at"`
+
+	// LastStatusCode is status code of the last response, zero if request failed.
+	LastStatusCode int `json:"last_status_code"`
+
+	// LastError describes failure of the last attempt.
+	LastError string `json:"last_error"`
+}
+
+// Options specifies options of Dispatcher.
+type Options struct {
+	// Endpoints receive every event. At least one is required.
+	Endpoints []Endpoint
+
+	// HTTPClient sends requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Timeout of single delivery attempt. Defaults to 10s.
+	Timeout time.Duration
+
+	// MaxAttempts after which delivery is marked as failed. Defaults to 10.
+	MaxAttempts int
+
+	// Concurrency limits number of endpoints delivered to concurrently. Defaults to 4.
+	Concurrency int
+
+	// MinBackoff is delay before the first retry, doubled with every next one up to MaxBackoff.
+	// Defaults to 10s and 1h.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Now returns current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Dispatcher delivers events from persistent outbox. It is safe for concurrent use.
+type Dispatcher struct {
+	db        *bolt.DB
+	options   Options
+	endpoints map[string]Endpoint
+
+	// mu serializes processing of the outbox.
+	mu   sync.Mutex
+	wake chan struct{}
+}
+
+// Open opens or creates outbox at path and returns Dispatcher delivering its events.
+// Deliveries pending from previous runs are resumed by Run.
+func Open(path string, options *Options) (*Dispatcher, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	opts := *options
+	if len(opts.Endpoints) == 0 {
+		return nil, tgtg.NewArgumentError("options", "no endpoints specified")
+	}
+	endpoints := make(map[string]Endpoint, len(opts.Endpoints))
+	for _, endpoint := range opts.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || !u.IsAbs() {
+			return nil, tgtg.NewArgumentError("options", fmt.Sprintf("endpoint URL %q is not absolute", endpoint.URL))
+		}
+		if endpoint.Secret == "" {
+			return nil, tgtg.NewArgumentError("options", fmt.Sprintf("endpoint %q has no secret", endpoint.URL))
+		}
+		endpoints[endpoint.URL] = endpoint
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 10 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{deliveriesBucket, dueBucket, retriesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Dispatcher{
+		db:        db,
+		options:   opts,
+		endpoints: endpoints,
+		wake:      make(chan struct{}, 1),
+	}, nil
+}
+
+// Close closes the outbox.
+func (d *Dispatcher) Close() error {
+	return d.db.Close()
+}
+
+// Enqueue stores event in the outbox as pending delivery to every endpoint. Deliveries to endpoint
+// with a retry scheduled are due no earlier than the retry. Once Enqueue returns, the event survives restarts.
+func (d *Dispatcher) Enqueue(events ...Event) error {
+	now := d.options.Now()
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		for _, event := range events {
+			for _, endpoint := range d.options.Endpoints {
+				id, err := tx.Bucket(deliveriesBucket).NextSequence()
+				if err != nil {
+					return err
+				}
+
+				next := now
+				if key := tx.Bucket(retriesBucket).Get([]byte(endpoint.URL)); key != nil && keyTime(key).After(now) {
+					next = keyTime(key)
+				}
+
+				err = saveDelivery(tx, nil, Delivery{
+					ID:          id,
+					Endpoint:    endpoint.URL,
+					Event:       event,
+					Status:      StatusPending,
+					CreatedAt:   now,
+					NextAttempt: next,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers pending events, see Process, until ctx is done or outbox fails.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		if err := d.Process(ctx); err != nil {
+			return err
+		}
+
+		next, err := d.nextAttempt()
+		if err != nil {
+			return err
+		}
+
+		// With nothing pending, wait for Enqueue or Redeliver only.
+		var (
+			timer   *time.Timer
+			elapsed <-chan time.Time
+		)
+		if !next.IsZero() {
+			timer = time.NewTimer(next.Sub(d.options.Now()))
+			elapsed = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-d.wake:
+		case <-elapsed:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Process makes single delivery attempt of every pending delivery that is due. Deliveries to different endpoints
+// are sent concurrently, up to Options.Concurrency endpoints at once, deliveries to the same endpoint one by one,
+// in order of enqueueing. Deliveries interrupted by ctx are left pending, with no attempt counted.
+func (d *Dispatcher) Process(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	due, err := d.due(d.options.Now())
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		first error
+		slots = make(chan struct{}, d.options.Concurrency)
+	)
+	for _, deliveries := range due {
+		wg.Add(1)
+		go func(deliveries []Delivery) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if err := d.processEndpoint(ctx, deliveries); err != nil {
+				mu.Lock()
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+			}
+		}(deliveries)
+	}
+	wg.Wait()
+
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
+
+// processEndpoint attempts deliveries to single endpoint one by one, storing their updated status.
+// Once delivery is scheduled for retry, the remaining ones are postponed to the retry, keeping their order.
+func (d *Dispatcher) processEndpoint(ctx context.Context, deliveries []Delivery) error {
+	for i, delivery := range deliveries {
+		previous := delivery
+		if !d.attempt(ctx, &delivery) {
+			return nil
+		}
+
+		err := d.db.Update(func(tx *bolt.Tx) error {
+			if err := saveDelivery(tx, &previous, delivery); err != nil {
+				return err
+			}
+			if delivery.Status != StatusPending {
+				return nil
+			}
+			return postpone(tx, delivery.Endpoint, delivery.NextAttempt, deliveries[i+1:])
+		})
+		if err != nil || delivery.Status == StatusPending {
+			return err
+		}
+	}
+	return nil
+}
+
+// postpone records retry of endpoint scheduled at given time, and postpones pending deliveries to it.
+func postpone(tx *bolt.Tx, endpoint string, retry time.Time, deliveries []Delivery) error {
+	if err := tx.Bucket(retriesBucket).Put([]byte(endpoint), timeKey(retry)); err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		previous := delivery
+		delivery.NextAttempt = retry
+		if err := saveDelivery(tx, &previous, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attempt sends delivery and updates its status. It reports false, leaving delivery unchanged,
+// if ctx is done before or while sending.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	endpoint, ok := d.endpoints[delivery.Endpoint]
+	if !ok {
+		delivery.Attempts++
+		delivery.Status = StatusFailed
+		delivery.CompletedAt = d.options.Now()
+		delivery.LastStatusCode = 0
+		delivery.LastError = fmt.Sprintf("webhook: endpoint %s is no longer configured", delivery.Endpoint)
+		return true
+	}
+
+	code, err := d.send(ctx, endpoint, delivery.Event)
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+
+	now := d.options.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = code
+	delivery.LastError = ""
+	switch {
+	case err == nil:
+		delivery.Status = StatusDelivered
+		delivery.CompletedAt = now
+	case !retryable(code) || delivery.Attempts >= d.options.MaxAttempts:
+		delivery.Status = StatusFailed
+		delivery.CompletedAt = now
+		delivery.LastError = err.Error()
+	default:
+		delivery.NextAttempt = now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError = err.Error()
+	}
+	return true
+}
+
+// send POSTs signed event to endpoint and returns response status code.
+func (d *Dispatcher) send(ctx context.Context, endpoint Endpoint, event Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.options.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.options.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, event.Type)
+	request.Header.Set(HeaderEventID, event.ID)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+
+	response, err := d.options.HTTPClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("webhook: endpoint responded with %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// retryable reports whether delivery which failed with given status code should be retried.
+// Zero code means no response was received.
+func retryable(code int) bool {
+	return code == 0 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// backoff returns delay before the next attempt, after given number of attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.options.MinBackoff
+	for i := 1; i < attempts && delay < d.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.options.MaxBackoff {
+		delay = d.options.MaxBackoff
+	}
+	return delay
+}
+
+// due returns pending deliveries due at given time by endpoint, in order of enqueueing.
+func (d *Dispatcher) due(now time.Time) (map[string][]Delivery, error) {
+	due := map[string][]Delivery{}
+	err := d.db.View(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket(deliveriesBucket)
+		limit := timeKey(now)
+
+		cursor := tx.Bucket(dueBucket).Cursor()
+		for key, _ := cursor.First(); key != nil && bytes.Compare(key[:8], limit) <= 0; key, _ = cursor.Next() {
+			var delivery Delivery
+			if err := json.Unmarshal(deliveries.Get(key[8:]), &delivery); err != nil {
+				return err
+			}
+			due[delivery.Endpoint] = append(due[delivery.Endpoint], delivery)
+		}
+		return nil
+	})
+	return due, err
+}
+
+// nextAttempt returns time of the earliest pending delivery attempt, zero if none is pending.
+func (d *Dispatcher) nextAttempt() (time.Time, error) {
+	var next time.Time
+	err := d.db.View(func(tx *bolt.Tx) error {
+		if key, _ := tx.Bucket(dueBucket).Cursor().First(); key != nil {
+			next = keyTime(key)
+		}
+		return nil
+	})
+	return next, err
+}
+
+// Deliveries returns deliveries with given status, all if status is empty, in order of enqueueing.
+func (d *Dispatcher) Deliveries(status Status) ([]Delivery, error) {
+	return d.deliveries(func(delivery Delivery) bool {
+		return status == "" || delivery.Status == status
+	})
+}
+
+// Delivery returns delivery with given ID, or false if there is none.
+func (d *Dispatcher) Delivery(id uint64) (Delivery, bool, error) {
+	var (
+		delivery Delivery
+		found    bool
+	)
+	err := d.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(deliveriesBucket).Get(idKey(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &delivery)
+	})
+	return delivery, found, err
+}
+
+// Counts returns number of deliveries by status.
+func (d *Dispatcher) Counts() (map[Status]int, error) {
+	counts := map[Status]int{}
+	_, err := d.deliveries(func(delivery Delivery) bool {
+		counts[delivery.Status]++
+		return false
+	})
+	return counts, err
+}
+
+// Redeliver schedules failed delivery with given ID for immediate delivery, with attempts counted from zero.
+func (d *Dispatcher) Redeliver(id uint64) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		data := tx.Bucket(deliveriesBucket).Get(idKey(id))
+		if data == nil {
+			return tgtg.NewArgumentError("id", fmt.Sprintf("delivery %d does not exist", id))
+		}
+
+		var delivery Delivery
+		if err := json.Unmarshal(data, &delivery); err != nil {
+			return err
+		}
+		if delivery.Status != StatusFailed {
+			return tgtg.NewArgumentError("id", fmt.Sprintf("delivery %d is %s, not failed", id, delivery.Status))
+		}
+
+		previous := delivery
+		delivery.Status = StatusPending
+		delivery.Attempts = 0
+		delivery.NextAttempt = d.options.Now()
+		delivery.CompletedAt = time.Time{}
+		return saveDelivery(tx, &previous, delivery)
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Prune removes delivered and failed deliveries completed before given time, and returns their number.
+func (d *Dispatcher) Prune(before time.Time) (int, error) {
+	var removed int
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		var keys [][]byte
+		cursor := tx.Bucket(deliveriesBucket).Cursor()
+		for key, data := cursor.First(); key != nil; key, data = cursor.Next() {
+			var delivery Delivery
+			if err := json.Unmarshal(data, &delivery); err != nil {
+				return err
+			}
+			if delivery.Status != StatusPending && delivery.CompletedAt.Before(before) {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, key := range keys {
+			if err := tx.Bucket(deliveriesBucket).Delete(key); err != nil {
+				return err
+			}
+		}
+		removed = len(keys)
+		return nil
+	})
+	return removed, err
+}
+
+// deliveries returns deliveries matching given function, in order of enqueueing.
+func (d *Dispatcher) deliveries(match func(Delivery) bool) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deliveriesBucket).ForEach(func(key, data []byte) error {
+			var delivery Delivery
+			if err := json.Unmarshal(data, &delivery); err != nil {
+				return err
+			}
+			if match(delivery) {
+				deliveries = append(deliveries, delivery)
+			}
+			return nil
+		})
+	})
+	return deliveries, err
+}
+
+// saveDelivery stores delivery under its ID, replacing previous version of it, if any, in index of pending deliveries.
+func saveDelivery(tx *bolt.Tx, previous *Delivery, delivery Delivery) error {
+	due := tx.Bucket(dueBucket)
+	if previous != nil && previous.Status == StatusPending {
+		if err := due.Delete(dueKey(*previous)); err != nil {
+			return err
+		}
+	}
+	if delivery.Status == StatusPending {
+		if err := due.Put(dueKey(delivery), nil); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(deliveriesBucket).Put(idKey(delivery.ID), data)
+}
+
+// dueKey returns key of pending delivery in index, sorted by time of the next attempt and order of enqueueing.
+func dueKey(delivery Delivery) []byte {
+	return append(timeKey(delivery.NextAttempt), idKey(delivery.ID)...)
+}
+
+// timeKey returns big endian encoded unix nanoseconds of t, so that keys are sorted chronologically.
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+// keyTime returns time encoded at the start of key by timeKey.
+func keyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8])))
+}
+
+// idKey returns big endian encoded delivery ID, so that keys are sorted in order of enqueueing.
+func idKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
@@ -0,0 +1,471 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver is an endpoint responding with scripted status codes, 200 once script runs out.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	events   []Event
+	received chan struct{}
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if !Verify("secret", timestamp, body, req.Header.Get(HeaderSignature)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var event Event
+	json.Unmarshal(body, &event)
+	if req.Header.Get(HeaderEvent) != event.Type || req.Header.Get(HeaderEventID) != event.ID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status == http.StatusOK {
+		r.events = append(r.events, event)
+		if r.received != nil {
+			r.received <- struct{}{}
+		}
+	}
+	w.WriteHeader(status)
+}
+
+// setup returns dispatcher delivering to receiver, and a clock controlling dispatcher's time.
+func setup(t *testing.T, statuses ...int) (*Dispatcher, *receiver, *time.Time) {
+	t.Helper()
+
+	r := &receiver{statuses: statuses}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	d, err := Open(filepath.Join(t.TempDir(), "outbox.db"), &Options{
+		Endpoints:   []Endpoint{{URL: server.URL, Secret: "secret"}},
+		MaxAttempts: 3,
+		Now:         func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	return d, r, &now
+}
+
+func event(t *testing.T, id string) Event {
+	t.Helper()
+	e, err := NewEvent(EventItemAvailable, time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC), ItemPayload{ItemID: id})
+	if err != nil {
+		t.Fatalf("NewEvent returned error: %+v", err)
+	}
+	return e
+}
+
+func TestDispatcher_Process(t *testing.T) {
+	testCases := []struct {
+		title    string
+		statuses []int
+		steps    []time.Duration
+		expected Delivery
+	}{
+		{
+			title:    "delivered",
+			expected: Delivery{Status: StatusDelivered, Attempts: 1, LastStatusCode: http.StatusOK},
+		},
+		{
+			title:    "retried",
+			statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests},
+			steps:    []time.Duration{10 * time.Second, 20 * time.Second},
+			expected: Delivery{Status: StatusDelivered, Attempts: 3, LastStatusCode: http.StatusOK},
+		},
+		{
+			title:    "retry not due",
+			statuses: []int{http.StatusBadGateway},
+			steps:    []time.Duration{9 * time.Second},
+			expected: Delivery{
+				Status:         StatusPending,
+				Attempts:       1,
+				LastStatusCode: http.StatusBadGateway,
+				LastError:      "webhook: endpoint responded with 502 Bad Gateway",
+			},
+		},
+		{
+			title:    "rejected",
+			statuses: []int{http.StatusGone},
+			expected: Delivery{
+				Status:         StatusFailed,
+				Attempts:       1,
+				LastStatusCode: http.StatusGone,
+				LastError:      "webhook: endpoint responded with 410 Gone",
+			},
+		},
+		{
+			title:    "attempts exhausted",
+			statuses: []int{500, 500, 500},
+			steps:    []time.Duration{10 * time.Second, 20 * time.Second},
+			expected: Delivery{
+				Status:         StatusFailed,
+				Attempts:       3,
+				LastStatusCode: http.StatusInternalServerError,
+				LastError:      "webhook: endpoint responded with 500 Internal Server Error",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			d, r, now := setup(t, tc.statuses...)
+			e := event(t, "1")
+			if err := d.Enqueue(e); err != nil {
+				t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+			}
+
+			if err := d.Process(context.Background()); err != nil {
+				t.Fatalf("Dispatcher.Process returned error: %+v", err)
+			}
+			for _, step := range tc.steps {
+				*now = now.Add(step)
+				if err := d.Process(context.Background()); err != nil {
+					t.Fatalf("Dispatcher.Process returned error: %+v", err)
+				}
+			}
+
+			deliveries, err := d.Deliveries("")
+			if err != nil {
+				t.Fatalf("Dispatcher.Deliveries returned error: %+v", err)
+			}
+			if len(deliveries) != 1 {
+				t.Fatalf("Dispatcher.Deliveries returned: %+v, expected single delivery", deliveries)
+			}
+
+			actual := deliveries[0]
+			if actual.Event.ID != e.ID || actual.ID != 1 {
+				t.Errorf("Delivery: %+v, expected delivery 1 of event %s", actual, e.ID)
+			}
+			actual = Delivery{Status: actual.Status, Attempts: actual.Attempts, LastStatusCode: actual.LastStatusCode, LastError: actual.LastError}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Delivery: %+v, expected: %+v", actual, tc.expected)
+			}
+
+			if delivered := tc.expected.Status == StatusDelivered; delivered != (len(r.events) == 1) {
+				t.Errorf("Received events: %+v, expected delivered: %+v", r.events, delivered)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Interrupted(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Body has to be read for request context to be cancelled once client goes away.
+		ioutil.ReadAll(r.Body)
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	d, err := Open(filepath.Join(t.TempDir(), "outbox.db"), &Options{Endpoints: []Endpoint{{URL: server.URL, Secret: "secret"}}})
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer d.Close()
+	if err := d.Enqueue(event(t, "1"), event(t, "2")); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if err := d.Process(ctx); err != context.Canceled {
+		t.Errorf("Dispatcher.Process returned: %+v, expected: %+v", err, context.Canceled)
+	}
+
+	deliveries, err := d.Deliveries(StatusPending)
+	if err != nil {
+		t.Fatalf("Dispatcher.Deliveries returned error: %+v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("Dispatcher.Deliveries returned: %+v, expected 2 pending deliveries", deliveries)
+	}
+	for _, delivery := range deliveries {
+		if delivery.Attempts != 0 || delivery.LastError != "" {
+			t.Errorf("Delivery: %+v, expected no attempt counted", delivery)
+		}
+	}
+}
+
+func TestDispatcher_RetryKeepsOrder(t *testing.T) {
+	d, r, now := setup(t, http.StatusServiceUnavailable)
+	events := []Event{event(t, "1"), event(t, "2"), event(t, "3")}
+	if err := d.Enqueue(events[:2]...); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+
+	// The first delivery fails, the second one waits for its retry.
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+	if len(r.events) != 0 {
+		t.Fatalf("Received events: %+v, expected none before retry", r.events)
+	}
+	second, _, err := d.Delivery(2)
+	if err != nil {
+		t.Fatalf("Dispatcher.Delivery returned error: %+v", err)
+	}
+	if second.Attempts != 0 || !second.NextAttempt.Equal(now.Add(10*time.Second)) {
+		t.Errorf("Delivery: %+v, expected to be postponed to retry", second)
+	}
+
+	// Event enqueued before the retry waits for it too.
+	*now = now.Add(5 * time.Second)
+	if err := d.Enqueue(events[2]); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+	if len(r.events) != 0 {
+		t.Fatalf("Received events: %+v, expected none before retry", r.events)
+	}
+
+	*now = now.Add(5 * time.Second)
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+
+	var actual, expected []string
+	for i := range r.events {
+		actual = append(actual, r.events[i].ID)
+	}
+	for i := range events {
+		expected = append(expected, events[i].ID)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Received events: %+v, expected: %+v", actual, expected)
+	}
+}
+
+func TestDispatcher_ConcurrentEndpoints(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+
+	fast := &receiver{received: make(chan struct{}, 2)}
+	fastServer := httptest.NewServer(fast)
+	defer fastServer.Close()
+
+	d, err := Open(filepath.Join(t.TempDir(), "outbox.db"), &Options{
+		Endpoints: []Endpoint{{URL: slow.URL, Secret: "secret"}, {URL: fastServer.URL, Secret: "secret"}},
+	})
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer d.Close()
+	if err := d.Enqueue(event(t, "1"), event(t, "2")); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- d.Process(context.Background()) }()
+
+	// Fast endpoint receives both events while slow one is still handling the first.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-fast.received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Event not delivered to fast endpoint.")
+		}
+	}
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+	if ids := []string{fast.events[0].ID, fast.events[1].ID}; ids[0] == ids[1] {
+		t.Errorf("Fast endpoint received: %+v, expected 2 different events", ids)
+	}
+
+	counts, err := d.Counts()
+	if err != nil {
+		t.Fatalf("Dispatcher.Counts returned error: %+v", err)
+	}
+	if expected := map[Status]int{StatusDelivered: 4}; !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Dispatcher.Counts returned: %+v, expected: %+v", counts, expected)
+	}
+}
+
+func TestDispatcher_Redeliver(t *testing.T) {
+	d, r, _ := setup(t, http.StatusNotFound)
+	if err := d.Enqueue(event(t, "1"), event(t, "2")); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+
+	counts, err := d.Counts()
+	if err != nil {
+		t.Fatalf("Dispatcher.Counts returned error: %+v", err)
+	}
+	if expected := map[Status]int{StatusFailed: 1, StatusDelivered: 1}; !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Dispatcher.Counts returned: %+v, expected: %+v", counts, expected)
+	}
+
+	if err := d.Redeliver(2); err == nil {
+		t.Error("Dispatcher.Redeliver of delivered delivery returned no error.")
+	}
+	if err := d.Redeliver(3); err == nil {
+		t.Error("Dispatcher.Redeliver of missing delivery returned no error.")
+	}
+	if err := d.Redeliver(1); err != nil {
+		t.Fatalf("Dispatcher.Redeliver returned error: %+v", err)
+	}
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+
+	delivery, found, err := d.Delivery(1)
+	if err != nil || !found {
+		t.Fatalf("Dispatcher.Delivery returned: %v, %+v, expected delivery", found, err)
+	}
+	if delivery.Status != StatusDelivered || delivery.Attempts != 1 {
+		t.Errorf("Delivery: %+v, expected delivered at first attempt", delivery)
+	}
+	if len(r.events) != 2 {
+		t.Errorf("Received events: %+v, expected 2", r.events)
+	}
+}
+
+func TestDispatcher_Prune(t *testing.T) {
+	d, _, now := setup(t, http.StatusOK, http.StatusServiceUnavailable)
+	if err := d.Enqueue(event(t, "1"), event(t, "2")); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+
+	removed, err := d.Prune(now.Add(time.Second))
+	if err != nil {
+		t.Fatalf("Dispatcher.Prune returned error: %+v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Dispatcher.Prune returned: %d, expected: 1", removed)
+	}
+
+	deliveries, err := d.Deliveries(StatusPending)
+	if err != nil {
+		t.Fatalf("Dispatcher.Deliveries returned error: %+v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != 2 {
+		t.Errorf("Dispatcher.Deliveries returned: %+v, expected pending delivery 2", deliveries)
+	}
+}
+
+func TestDispatcher_Persistence(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	options := &Options{Endpoints: []Endpoint{{URL: server.URL, Secret: "secret"}}}
+
+	d, err := Open(path, options)
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	e := event(t, "1")
+	if err := d.Enqueue(e); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+	d.Close()
+
+	d, err = Open(path, options)
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer d.Close()
+	if err := d.Process(context.Background()); err != nil {
+		t.Fatalf("Dispatcher.Process returned error: %+v", err)
+	}
+
+	if len(r.events) != 1 || r.events[0].ID != e.ID {
+		t.Errorf("Received events: %+v, expected event %s", r.events, e.ID)
+	}
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	r := &receiver{received: make(chan struct{}, 1)}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	d, err := Open(filepath.Join(t.TempDir(), "outbox.db"), &Options{Endpoints: []Endpoint{{URL: server.URL, Secret: "secret"}}})
+	if err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer d.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+
+	if err := d.Enqueue(event(t, "1")); err != nil {
+		t.Fatalf("Dispatcher.Enqueue returned error: %+v", err)
+	}
+	select {
+	case <-r.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event not delivered.")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Dispatcher.Run returned: %+v, expected: %+v", err, context.Canceled)
+	}
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	testCases := []struct {
+		title   string
+		options *Options
+	}{
+		{title: "nil options"},
+		{title: "no endpoints", options: &Options{}},
+		{title: "relative URL", options: &Options{Endpoints: []Endpoint{{URL: "/hooks", Secret: "secret"}}}},
+		{title: "no secret", options: &Options{Endpoints: []Endpoint{{URL: "https://example.com/hooks"}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if _, err := Open(filepath.Join(t.TempDir(), "outbox.db"), tc.options); err == nil {
+				t.Error("Open returned no error.")
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package webhook delivers Too Good To Go item and order events to HTTP endpoints.
+// Events are signed with HMAC-SHA256, kept in a persistent outbox on disk, so that
+// they survive restarts, and retried with exponential backoff until delivered.
+//
+//	dispatcher, err := webhook.Open("outbox.db", &webhook.Options{
+//		Endpoints: []webhook.Endpoint{{URL: "https://example.com/hooks/tgtg", Secret: secret}},
+//	})
+//	...
+//	go dispatcher.Run(ctx)
+//	event, err := webhook.ItemEvent(items, time.Now())
+//	...
+//	err = dispatcher.Enqueue(event)
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+// Headers set on every delivery request.
+const (
+	// HeaderEvent carries event type.
+	HeaderEvent = "X-TGTG-Event"
+
+	// HeaderEventID carries event ID, the same for every delivery attempt, allowing receivers to deduplicate.
+	HeaderEventID = "X-TGTG-Event-ID"
+
+	// HeaderTimestamp carries unix time of delivery attempt, in seconds.
+	HeaderTimestamp = "X-TGTG-Timestamp"
+
+	// HeaderSignature carries signature of the request, see Sign.
+	HeaderSignature = "X-TGTG-Signature"
+)
+
+// Event types.
+const (
+	EventItemAvailable = "item.available"
+	EventItemSoldOut   = "item.sold_out"
+	EventOrder         = "order.updated"
+)
+
+// Event is a JSON payload POSTed to endpoints.
+type Event struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+// ItemPayload is data of item events.
+type ItemPayload struct {
+	ItemID         string     `json:"item_id"`
+	StoreID        string     `json:"store_id"`
+	StoreName      string     `json:"store_name"`
+	DisplayName    string     `json:"display_name"`
+	ItemsAvailable int        `json:"items_available"`
+	Price          tgtg.Price `json:"price"`
+	Value          tgtg.Price `json:"value"`
+	PickupStart    time.Time  `json:"pickup_start"`
+	PickupEnd      time.Time  `json:"pickup_end"`
+	Distance       float64    `json:"distance"`
+}
+
+// OrderPayload is data of order events.
+type OrderPayload struct {
+	OrderID     string     `json:"order_id"`
+	State       string     `json:"state"`
+	ItemID      string     `json:"item_id"`
+	ItemName    string     `json:"item_name"`
+	StoreID     string     `json:"store_id"`
+	StoreName   string     `json:"store_name"`
+	Quantity    int        `json:"quantity"`
+	Price       tgtg.Price `json:"price"`
+	PickupStart time.Time  `json:"pickup_start"`
+	PickupEnd   time.Time  `json:"pickup_end"`
+	CancelUntil time.Time  `json:"cancel_until"`
+}
+
+// NewEvent returns Event of given type with data encoded as JSON and a random ID.
+func NewEvent(eventType string, at time.Time, data interface{}) (Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Event{}, err
+	}
+
+	return Event{ID: hex.EncodeToString(id), Type: eventType, Time: at, Data: encoded}, nil
+}
+
+// ItemEvent returns item.available or item.sold_out Event of items entry returned by Items.List.
+func ItemEvent(items tgtg.Items, at time.Time) (Event, error) {
+	eventType := EventItemAvailable
+	if items.ItemsAvailable == 0 {
+		eventType = EventItemSoldOut
+	}
+
+	return NewEvent(eventType, at, ItemPayload{
+		ItemID:         items.Item.ItemID,
+		StoreID:        items.Store.StoreID,
+		StoreName:      items.Store.StoreName,
+		DisplayName:    items.DisplayName,
+		ItemsAvailable: items.ItemsAvailable,
+		Price:          items.Item.PriceIncludingTaxes,
+		Value:          items.Item.ValueIncludingTaxes,
+		PickupStart:    items.PickupInterval.Start.Time,
+		PickupEnd:      items.PickupInterval.End.Time,
+		Distance:       items.Distance,
+	})
+}
+
+// OrderEvent returns order.updated Event of order returned by Orders service.
+func OrderEvent(order tgtg.Order, at time.Time) (Event, error) {
+	return NewEvent(EventOrder, at, OrderPayload{
+		OrderID:     order.OrderID,
+		State:       order.State,
+		ItemID:      order.ItemID,
+		ItemName:    order.ItemName,
+		StoreID:     order.StoreID,
+		StoreName:   order.StoreName,
+		Quantity:    order.Quantity,
+		Price:       order.PriceIncludingTaxes,
+		PickupStart: order.PickupInterval.Start.Time,
+		PickupEnd:   order.PickupInterval.End.Time,
+		CancelUntil: order.CancelUntil.Time,
+	})
+}
+
+// Sign returns signature of request body sent at given unix time: "sha256=" followed by
+// hex encoded HMAC-SHA256, keyed with secret, of timestamp, ".", and body.
+// Signing timestamp along with body allows receivers to reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches request body sent at given unix time, see Sign.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	tgtg "github.com/filippalach/tgt-go"
+)
+
+func TestItemEvent(t *testing.T) {
+	at := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	items := tgtg.Items{
+		Item:           tgtg.Item{ItemID: "1", PriceIncludingTaxes: tgtg.Price{Code: "EUR", Decimals: 2, MinorUnits: 399}},
+		Store:          tgtg.Store{StoreID: "s1", StoreName: "store"},
+		ItemsAvailable: 2,
+		Distance:       1.5,
+	}
+
+	testCases := []struct {
+		title     string
+		available int
+		expected  string
+	}{
+		{title: "available", available: 2, expected: EventItemAvailable},
+		{title: "sold out", available: 0, expected: EventItemSoldOut},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			items.ItemsAvailable = tc.available
+			event, err := ItemEvent(items, at)
+			if err != nil {
+				t.Fatalf("ItemEvent returned error: %+v", err)
+			}
+			if event.Type != tc.expected || !event.Time.Equal(at) || len(event.ID) != 32 {
+				t.Errorf("ItemEvent returned: %+v, expected %s event", event, tc.expected)
+			}
+
+			var payload ItemPayload
+			if err := json.Unmarshal(event.Data, &payload); err != nil {
+				t.Fatalf("Unmarshal returned error: %+v", err)
+			}
+			expected := ItemPayload{
+				ItemID:         "1",
+				StoreID:        "s1",
+				StoreName:      "store",
+				ItemsAvailable: tc.available,
+				Price:          tgtg.Price{Code: "EUR", Decimals: 2, MinorUnits: 399},
+				Distance:       1.5,
+			}
+			if !reflect.DeepEqual(payload, expected) {
+				t.Errorf("ItemEvent payload: %+v, expected: %+v", payload, expected)
+			}
+		})
+	}
+}
+
+func TestOrderEvent(t *testing.T) {
+	order := tgtg.Order{OrderID: "o1", State: "ACTIVE", ItemID: "1", StoreName: "store", Quantity: 2}
+	event, err := OrderEvent(order, time.Now())
+	if err != nil {
+		t.Fatalf("OrderEvent returned error: %+v", err)
+	}
+
+	var payload OrderPayload
+	if err := json.Unmarshal(event.Data, &payload); err != nil {
+		t.Fatalf("Unmarshal returned error: %+v", err)
+	}
+	if event.Type != EventOrder || payload.OrderID != "o1" || payload.State != "ACTIVE" || payload.Quantity != 2 {
+		t.Errorf("OrderEvent returned: %+v with payload %+v, expected order o1", event, payload)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret", 1638360000, body)
+
+	// echo -n '1638360000.{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=1a3c9393df3bd5c2732ba1346bac88a457541015fae371af71c8de8c0031d0de"
+	if signature != expected {
+		t.Errorf("Sign returned: %s, expected: %s", signature, expected)
+	}
+
+	testCases := []struct {
+		title     string
+		secret    string
+		timestamp int64
+		body      []byte
+		expected  bool
+	}{
+		{title: "valid", secret: "secret", timestamp: 1638360000, body: body, expected: true},
+		{title: "other secret", secret: "other", timestamp: 1638360000, body: body},
+		{title: "other timestamp", secret: "secret", timestamp: 1638360001, body: body},
+		{title: "other body", secret: "secret", timestamp: 1638360000, body: []byte(`{"id":"2"}`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if actual := Verify(tc.secret, tc.timestamp, tc.body, signature); actual != tc.expected {
+				t.Errorf("Verify returned: %+v, expected: %+v", actual, tc.expected)
+			}
+		})
+	}
+}